@@ -0,0 +1,60 @@
+// Package pafeed holds helpers shared by horse and greyhound racing feed
+// parsers. Sport specific documents are parsed by horses and greyhounds
+// subpackages.
+package pafeed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/advbet/pafeed/internal/xmldoc"
+)
+
+// DocumentType is an enum of PA feed document types identified by the
+// document root element name.
+type DocumentType string
+
+// List of allowed DocumentType values.
+const (
+	DocumentHorseRacing     DocumentType = "HorseRacing"     // horse racing results and shows, see horses.ParseRacingFile
+	DocumentHorseRacingCard DocumentType = "HorseRacingCard" // horse racing card, see horses.ParseRacingCardFile
+	DocumentDogRacing       DocumentType = "DogRacing"       // greyhound racing card or race, see greyhounds.ParseFile
+)
+
+func (t DocumentType) isValid() bool {
+	switch t {
+	case DocumentHorseRacing,
+		DocumentHorseRacingCard,
+		DocumentDogRacing:
+		return true
+	default:
+		return false
+	}
+}
+
+// DetectDocument returns document type of XML file contents. Only the root
+// element name is read, document is not unmarshaled. Unlike IsRacingFile and
+// IsRacingCardFile checks of horses package this does not rely on file names.
+func DetectDocument(xmlBlob []byte) (DocumentType, error) {
+	d := xmldoc.NewDecoder(bytes.NewReader(xmlBlob))
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return "", fmt.Errorf("root element not found")
+		}
+		if err != nil {
+			return "", err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		typ := DocumentType(start.Name.Local)
+		if !typ.isValid() {
+			return "", fmt.Errorf("unknown document root element: %s", start.Name.Local)
+		}
+		return typ, nil
+	}
+}
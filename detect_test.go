@@ -0,0 +1,57 @@
+package pafeed
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectDocument(t *testing.T) {
+	tests := []struct {
+		file string
+		typ  DocumentType
+	}{
+		{
+			file: "horses/testdata/feed/b20181128wth12150045.xml",
+			typ:  DocumentHorseRacing,
+		},
+		{
+			file: "horses/testdata/feed/c20190227rsh.xml",
+			typ:  DocumentHorseRacingCard,
+		},
+		{
+			file: "greyhounds/testdata/Crayford/b2018041433736119270028.xml",
+			typ:  DocumentDogRacing,
+		},
+		{
+			file: "greyhounds/testdata/Crayford/c20180414cra5_337361.xml",
+			typ:  DocumentDogRacing,
+		},
+		{
+			file: "horses/testdata/Synthetic/windows-1252-racing.xml",
+			typ:  DocumentHorseRacing,
+		},
+	}
+
+	for _, test := range tests {
+		blob, err := ioutil.ReadFile(test.file)
+		require.NoError(t, err, test.file)
+		typ, err := DetectDocument(blob)
+		require.NoError(t, err, test.file)
+		assert.Equal(t, test.typ, typ, test.file)
+	}
+}
+
+func TestDetectDocumentErrors(t *testing.T) {
+	for _, blob := range []string{
+		``,
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<?xml version="1.0" encoding="UTF-8"?><Unknown/>`,
+		`not xml <`,
+	} {
+		_, err := DetectDocument([]byte(blob))
+		assert.Error(t, err, blob)
+	}
+}
@@ -0,0 +1,64 @@
+package pafeed
+
+import (
+	"math/big"
+	"strings"
+)
+
+// namedDistances maps beaten distance names and abbreviations to approximate
+// values in lengths.
+var namedDistances = map[string]float64{
+	"nose":       0.05,
+	"nse":        0.05,
+	"short head": 0.1,
+	"shd":        0.1,
+	"sh":         0.1,
+	"head":       0.2,
+	"hd":         0.2,
+	"short neck": 0.25,
+	"snk":        0.25,
+	"neck":       0.3,
+	"nk":         0.3,
+	"distance":   30,
+	"dist":       30,
+	"dis":        30,
+	"ds":         30,
+}
+
+// ParseBeatenDistance parses beaten (between) distance text used by both horse
+// and greyhound results to a number of lengths. Distances are either written
+// as a fractional number of lengths (e.g. "1 1/4 length", "17 lengths" or
+// "3/4") or named (e.g. "Short Head", "nk", "hd"). Named distances are mapped
+// to their approximate values in lengths, "distance" is treated as 30
+// lengths. False is returned for dead-heats and for unknown or empty text.
+func ParseBeatenDistance(s string) (lengths float64, ok bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if l, ok := namedDistances[s]; ok {
+		return l, true
+	}
+
+	fields := strings.Fields(s)
+	if n := len(fields); n > 0 {
+		switch fields[n-1] {
+		case "length", "lengths", "len", "l":
+			fields = fields[:n-1]
+		}
+	}
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0, false
+	}
+	// whole lengths can be followed by a fraction only, e.g. "1 1/4"
+	if len(fields) == 2 && (strings.Contains(fields[0], "/") || !strings.Contains(fields[1], "/")) {
+		return 0, false
+	}
+	var total big.Rat
+	for _, f := range fields {
+		r, ok := new(big.Rat).SetString(f)
+		if !ok || r.Sign() < 0 {
+			return 0, false
+		}
+		total.Add(&total, r)
+	}
+	lengths, _ = total.Float64()
+	return lengths, true
+}
@@ -0,0 +1,45 @@
+package pafeed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBeatenDistance(t *testing.T) {
+	tests := []struct {
+		text    string
+		lengths float64
+		ok      bool
+	}{
+		{text: "17 lengths", lengths: 17, ok: true},
+		{text: "1 1/4 length", lengths: 1.25, ok: true},
+		{text: "3/4 length", lengths: 0.75, ok: true},
+		{text: "1 length", lengths: 1, ok: true},
+		{text: "2 1/2", lengths: 2.5, ok: true},
+		{text: "1/2", lengths: 0.5, ok: true},
+		{text: "10", lengths: 10, ok: true},
+		{text: "Neck", lengths: 0.3, ok: true},
+		{text: "nk", lengths: 0.3, ok: true},
+		{text: "Head", lengths: 0.2, ok: true},
+		{text: "HD", lengths: 0.2, ok: true},
+		{text: "Short Head", lengths: 0.1, ok: true},
+		{text: "SH", lengths: 0.1, ok: true},
+		{text: "Nose", lengths: 0.05, ok: true},
+		{text: "DIS", lengths: 30, ok: true},
+		{text: "Dead Heat", ok: false},
+		{text: "dht", ok: false},
+		{text: "DH", ok: false},
+		{text: "DNF", ok: false},
+		{text: "", ok: false},
+		{text: "1/2 1", ok: false},
+		{text: "1 2", ok: false},
+		{text: "lengths", ok: false},
+	}
+
+	for _, test := range tests {
+		lengths, ok := ParseBeatenDistance(test.text)
+		assert.Equal(t, test.ok, ok, test.text)
+		assert.InDelta(t, test.lengths, lengths, 1e-9, test.text)
+	}
+}
@@ -0,0 +1,220 @@
+package greyhounds
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Change is a single field level change between two states of a race.
+type Change struct {
+	Path string      // Changed field, e.g. "state" or "trap 2 result"
+	Old  interface{} // Value in the old race state, nil if absent
+	New  interface{} // Value in the new race state, nil if absent
+}
+
+// DiffRace returns changes between two states of the same race. Reported
+// changes are:
+//
+//   - "state", "off time" and "win time" of the race;
+//   - "trap <number> vacant" and "trap <number> dog" (dog ID) of every trap;
+//   - "trap <number> show" for every show not present in the old race, old
+//     value is always nil;
+//   - "trap <number> result" of every trap;
+//   - "non runner <trap number>" for every added non runner, old value is
+//     always nil;
+//   - "dividends" when dividends are posted or removed, changes of posted
+//     dividends are not reported.
+//
+// Values are of the field types, optional fields are dereferenced. Traps are
+// reported in the order of the new race, changes of removed traps are not
+// reported.
+func DiffRace(old, new Race) []Change {
+	var changes []Change
+	add := func(path string, o, n interface{}) {
+		changes = append(changes, Change{Path: path, Old: o, New: n})
+	}
+
+	if old.State != new.State {
+		add("state", old.State, new.State)
+	}
+	if !equalTimes(old.OffTime, new.OffTime) {
+		add("off time", timeValue(old.OffTime), timeValue(new.OffTime))
+	}
+	if old.WinTime != new.WinTime {
+		add("win time", old.WinTime, new.WinTime)
+	}
+
+	for i := range new.Traps {
+		t := &new.Traps[i]
+		prefix := fmt.Sprintf("trap %d", t.TrapNo)
+		o, ok := old.TrapByNumber(t.TrapNo)
+		if !ok {
+			o = &Trap{}
+		}
+		if o.Vacant != t.Vacant {
+			add(prefix+" vacant", o.Vacant, t.Vacant)
+		}
+		if od, nd := dogID(o.Dog), dogID(t.Dog); od != nd {
+			add(prefix+" dog", od, nd)
+		}
+		for _, s := range t.Shows {
+			if !hasShow(o.Shows, s) {
+				add(prefix+" show", nil, s)
+			}
+		}
+		if !reflect.DeepEqual(o.Result, t.Result) {
+			add(prefix+" result", resultValue(o.Result), resultValue(t.Result))
+		}
+	}
+
+	for _, nr := range new.NonRunners {
+		found := false
+		for _, o := range old.NonRunners {
+			if o.Trap == nr.Trap {
+				found = true
+				break
+			}
+		}
+		if !found {
+			add(fmt.Sprintf("non runner %d", nr.Trap), nil, nr)
+		}
+	}
+
+	if (old.Dividends == nil) != (new.Dividends == nil) {
+		add("dividends", dividendsValue(old.Dividends), dividendsValue(new.Dividends))
+	}
+	return changes
+}
+
+// hasShow returns true if shows contain a show with the same time stamp and
+// market number.
+func hasShow(shows []Show, show Show) bool {
+	for _, s := range shows {
+		if s.TimeStamp.Equal(show.TimeStamp) && s.Market() == show.Market() {
+			return true
+		}
+	}
+	return false
+}
+
+// dogID returns ID of the dog or nil if dog is absent.
+func dogID(d *Dog) interface{} {
+	if d == nil {
+		return nil
+	}
+	return d.ID
+}
+
+// equalTimes returns true if both times are absent or represent the same time
+// instant.
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// timeValue returns dereferenced time or nil.
+func timeValue(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+// resultValue returns dereferenced result or nil.
+func resultValue(r *Result) interface{} {
+	if r == nil {
+		return nil
+	}
+	return *r
+}
+
+// dividendsValue returns dividends or nil if dividends are absent.
+func dividendsValue(d *Dividends) interface{} {
+	if d == nil {
+		return nil
+	}
+	return d
+}
+
+// PriceDirection is the direction of a price movement.
+type PriceDirection string
+
+// Possible price movement directions.
+const (
+	PriceSteamer PriceDirection = "Steamer" // Price has shortened
+	PriceDrifter PriceDirection = "Drifter" // Price has lengthened
+)
+
+// PriceChange is a price movement of a single trap between two states of a
+// race.
+type PriceChange struct {
+	TrapNo    int            // The number of the trap
+	Name      string         // The name of the dog, empty if trap has no dog
+	Old       Price          // Price of the latest show in the old race
+	New       Price          // Price of the latest show in the new race
+	Direction PriceDirection // Whether the dog is a steamer or a drifter
+}
+
+// String returns price change description, e.g. "Pesky Pigeon shortened from
+// 5/1 to 9/2". Trap number is used if dog name is not known.
+func (c PriceChange) String() string {
+	name := c.Name
+	if name == "" {
+		name = fmt.Sprintf("Trap %d", c.TrapNo)
+	}
+	verb := "shortened"
+	if c.Direction == PriceDrifter {
+		verb = "drifted"
+	}
+	return fmt.Sprintf("%s %s from %s to %s", name, verb, c.Old.FractionalString(), c.New.FractionalString())
+}
+
+// PriceChanges returns price movements of traps between two states of the
+// same race. Price of the latest offered show of the first betting market in
+// each race is compared, see LatestShow and Price.Fraction. Traps not having
+// an offered show in either of the races or having unchanged prices are not
+// reported. Changes are returned in the order of traps in the new race.
+func PriceChanges(old, new Race) []PriceChange {
+	var changes []PriceChange
+	for i := range new.Traps {
+		t := &new.Traps[i]
+		n, ok := t.LatestShow(1)
+		if !ok {
+			continue
+		}
+		ot, ok := old.TrapByNumber(t.TrapNo)
+		if !ok {
+			continue
+		}
+		o, ok := ot.LatestShow(1)
+		if !ok {
+			continue
+		}
+		of, nf := o.Price.Fraction(), n.Price.Fraction()
+		if of == nil || nf == nil {
+			continue
+		}
+		direction := PriceSteamer
+		switch of.Cmp(nf) {
+		case 0:
+			continue
+		case -1:
+			direction = PriceDrifter
+		}
+		var name string
+		if t.Dog != nil {
+			name = t.Dog.Name
+		}
+		changes = append(changes, PriceChange{
+			TrapNo:    t.TrapNo,
+			Name:      name,
+			Old:       *o.Price,
+			New:       *n.Price,
+			Direction: direction,
+		})
+	}
+	return changes
+}
@@ -0,0 +1,129 @@
+package greyhounds
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffRace(t *testing.T) {
+	running := testRace(t, "testdata/Crayford/b2018041433736119270026.xml")
+	result := testRace(t, "testdata/Crayford/b2018041433736119270028.xml")
+
+	assert.Empty(t, DiffRace(result, result))
+
+	changes := DiffRace(running, result)
+	require.NotEmpty(t, changes)
+	assert.Equal(t, Change{Path: "state", Old: RaceHareRunning, New: RaceFinalResult}, changes[0])
+
+	paths := make(map[string]Change)
+	for _, c := range changes {
+		paths[c.Path] = c
+	}
+	require.Contains(t, paths, "dividends")
+	assert.Nil(t, paths["dividends"].Old)
+	assert.Equal(t, result.Dividends, paths["dividends"].New)
+	winners := result.Winners()
+	require.NotEmpty(t, winners)
+	path := fmt.Sprintf("trap %d result", winners[0].TrapNo)
+	require.Contains(t, paths, path)
+	assert.Nil(t, paths[path].Old)
+	assert.Equal(t, *winners[0].Result, paths[path].New)
+}
+
+func TestDiffRaceTraps(t *testing.T) {
+	old := Race{
+		Traps: []Trap{
+			{TrapNo: 1, Dog: &Dog{ID: 10}},
+			{TrapNo: 2, Dog: &Dog{ID: 20}},
+		},
+	}
+	new := Race{
+		Traps: []Trap{
+			{
+				TrapNo: 1,
+				Dog:    &Dog{ID: 10},
+				Shows:  []Show{{TimeStamp: makeTime(t, "2018-04-14T11:02:00+01:00"), Price: &Price{Decimal: makeDecimal(t, "3.5")}}},
+			},
+			{TrapNo: 2, Vacant: true},
+		},
+		NonRunners: []NonRunner{{Trap: 2, Reason: WithdrawalLame, Dog: &Dog{ID: 20}}},
+	}
+
+	assert.Equal(t, []Change{
+		{Path: "trap 1 show", Old: nil, New: new.Traps[0].Shows[0]},
+		{Path: "trap 2 vacant", Old: false, New: true},
+		{Path: "trap 2 dog", Old: 20, New: nil},
+		{Path: "non runner 2", Old: nil, New: new.NonRunners[0]},
+	}, DiffRace(old, new))
+}
+
+func TestPriceChanges(t *testing.T) {
+	tests := []struct {
+		old, new string
+		changes  []string
+	}{
+		{
+			old:     "testdata/Crayford/b2018041433736119270007.xml",
+			new:     "testdata/Crayford/b2018041433736119270008.xml",
+			changes: []string{"Clonmannon Lady drifted from 6/1 to 7/1"},
+		},
+		{
+			old:     "testdata/Crayford/b2018041433736119270008.xml",
+			new:     "testdata/Crayford/b2018041433736119270009.xml",
+			changes: []string{"Pesky Pigeon shortened from 5/1 to 9/2"},
+		},
+		{
+			old: "testdata/Crayford/b2018041433736119270012.xml",
+			new: "testdata/Crayford/b2018041433736119270015.xml",
+			changes: []string{
+				"Kelva Matty drifted from 2/1 to 9/4",
+				"Pesky Pigeon shortened from 9/2 to 4/1",
+				"Aoifes Speedy drifted from 7/1 to 10/1",
+			},
+		},
+		{
+			old: "testdata/Crayford/b2018041433736119270008.xml",
+			new: "testdata/Crayford/b2018041433736119270008.xml",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.new, func(t *testing.T) {
+			var changes []string
+			for _, c := range PriceChanges(testRace(t, test.old), testRace(t, test.new)) {
+				changes = append(changes, c.String())
+			}
+			assert.Equal(t, test.changes, changes)
+		})
+	}
+}
+
+func TestPriceChangesDirection(t *testing.T) {
+	ts := makeTime(t, "2018-04-14T11:02:00+01:00")
+	old := Race{
+		Traps: []Trap{
+			{TrapNo: 1, Shows: []Show{{TimeStamp: ts, Price: &Price{Decimal: makeDecimal(t, "5")}}}},
+		},
+	}
+	new := Race{
+		Traps: []Trap{
+			{TrapNo: 1, Shows: []Show{
+				{TimeStamp: ts, Price: &Price{Decimal: makeDecimal(t, "5")}},
+				{TimeStamp: ts.Add(time.Minute), Price: &Price{Decimal: makeDecimal(t, "6")}},
+				{TimeStamp: ts.Add(2 * time.Minute), NoOffers: true},
+				{TimeStamp: ts.Add(3 * time.Minute), MarketNumber: intPtr(2), Price: &Price{Decimal: makeDecimal(t, "2")}},
+			}},
+		},
+	}
+
+	changes := PriceChanges(old, new)
+	require.Len(t, changes, 1)
+	assert.Equal(t, 1, changes[0].TrapNo)
+	assert.Equal(t, PriceDrifter, changes[0].Direction)
+	assert.Equal(t, "Trap 1 drifted from 5/1 to 6/1", changes[0].String())
+	assert.Empty(t, PriceChanges(new, new))
+}
@@ -0,0 +1,98 @@
+package greyhounds
+
+import (
+	"io/fs"
+	"strings"
+	"time"
+)
+
+// ParsedFile is a result of parsing a single feed file by ParseDir.
+type ParsedFile struct {
+	Path string       // Path of the file in the walked file system
+	Card bool         // Whether the file is a race card file
+	Info FeedFileInfo // Metadata of the race file, zero for card files
+	Obj  *DogRacing   // Parsed document, nil if parsing failed
+	Err  error        // Error parsing the file
+}
+
+// ParseDir walks directory tree rooted at root and parses every feed file
+// found. Race files have b<digits>.xml names, see ParseFilename, card files
+// have names starting with "c" and .xml extension. Gzip compressed files having
+// extra .gz extension are parsed too, other files are skipped. Race file Info
+// is taken from the file name, if the name can not be parsed or does not match
+// the single race document it is filled from the document instead. Files that
+// fail to parse are returned with Err set, walking is not aborted. Error is
+// returned only if walking the directory fails.
+func ParseDir(fsys fs.FS, root string) ([]ParsedFile, error) {
+	var files []ParsedFile
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		name := strings.TrimSuffix(d.Name(), ".gz")
+		if !strings.HasSuffix(name, ".xml") {
+			return nil
+		}
+		file := ParsedFile{Path: p}
+		switch {
+		case strings.HasPrefix(name, "b"):
+		case strings.HasPrefix(name, "c"):
+			file.Card = true
+		default:
+			return nil
+		}
+		file.Obj, file.Err = parseFSFile(fsys, p)
+		if !file.Card {
+			file.Info = raceFileInfo(name, file.Obj)
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// raceFileInfo returns metadata of a race file. Metadata is parsed from the
+// file name and cross-checked with the document if it holds a single race, the
+// document is used if the name does not match it. Obj can be nil.
+func raceFileInfo(name string, obj *DogRacing) FeedFileInfo {
+	info, err := ParseFilename(name)
+	if obj == nil || len(obj.Meetings) != 1 || len(obj.Meetings[0].Races) != 1 {
+		return info
+	}
+	m := obj.Meetings[0]
+	r := m.Races[0]
+	if err == nil &&
+		info.MeetingID == m.MeetingID &&
+		info.Date.Format("20060102") == m.Date.Format("20060102") &&
+		(info.RaceNumber == 0 || info.RaceNumber == r.RaceNumber) &&
+		(info.RaceTime == "" || info.RaceTime == r.Time.Format("1504")) {
+		return info
+	}
+	return FeedFileInfo{
+		Date:       time.Date(m.Date.Year(), m.Date.Month(), m.Date.Day(), 0, 0, 0, 0, time.UTC),
+		MeetingID:  m.MeetingID,
+		RaceTime:   r.Time.Format("1504"),
+		RaceNumber: r.RaceNumber,
+		Revision:   r.Revision,
+	}
+}
+
+// parseFSFile parses a single file of the file system, see ParseReader.
+func parseFSFile(fsys fs.FS, name string) (*DogRacing, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	obj, err := ParseReader(f)
+	if err != nil {
+		return nil, &fs.PathError{Op: "parse", Path: name, Err: err}
+	}
+	return obj, nil
+}
@@ -0,0 +1,98 @@
+package greyhounds
+
+import (
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDir(t *testing.T) {
+	race, err := ioutil.ReadFile("testdata/Crayford/b2018041433736119270028.xml")
+	require.NoError(t, err)
+	card, err := ioutil.ReadFile("testdata/Crayford/c20180414cra5_337361.xml")
+	require.NoError(t, err)
+	gzipped, err := ioutil.ReadFile("testdata/gzip/b2018041433736119270028.xml.gz")
+	require.NoError(t, err)
+	meadows, err := ioutil.ReadFile("testdata/The Meadows/b201804143181060038.xml")
+	require.NoError(t, err)
+
+	fsys := fstest.MapFS{
+		"drop/b2018041433736119270028.xml":         {Data: race},
+		"drop/c20180414cra5_337361.xml":            {Data: card},
+		"drop/late/b2018041433736119270029.xml":    {Data: []byte("<DogRacing><Meeting")},
+		"drop/late/b2018041433736119270030.gz":     {Data: gzipped},
+		"drop/late/b2018041433736119270031.xml.gz": {Data: gzipped},
+		"drop/b2018.xml":                           {Data: race},
+		"drop/b201804143373611927.xml":             {Data: meadows},
+		"drop/README.txt":                          {Data: []byte("hourly drop")},
+		"other/b2018041433736119270028.xml":        {Data: race},
+	}
+
+	files, err := ParseDir(fsys, "drop")
+	require.NoError(t, err)
+	require.Len(t, files, 6)
+	expected := parseTestFile(t, "testdata/Crayford/b2018041433736119270028.xml")
+	crayford := FeedFileInfo{
+		Date:       time.Date(2018, 4, 14, 0, 0, 0, 0, time.UTC),
+		MeetingID:  337361,
+		RaceTime:   "1927",
+		RaceNumber: 1,
+		Revision:   28,
+	}
+
+	assert.Equal(t, "drop/b2018.xml", files[0].Path)
+	require.NoError(t, files[0].Err)
+	assert.Equal(t, expected, files[0].Obj)
+	assert.Equal(t, crayford, files[0].Info)
+
+	assert.Equal(t, "drop/b201804143373611927.xml", files[1].Path)
+	require.NoError(t, files[1].Err)
+	assert.Equal(t, FeedFileInfo{
+		Date:       time.Date(2018, 4, 14, 0, 0, 0, 0, time.UTC),
+		MeetingID:  3181,
+		RaceTime:   "1057",
+		RaceNumber: 6,
+		Revision:   38,
+	}, files[1].Info)
+
+	assert.Equal(t, "drop/b2018041433736119270028.xml", files[2].Path)
+	require.NoError(t, files[2].Err)
+	assert.Equal(t, expected, files[2].Obj)
+	assert.Equal(t, 337361, files[2].Info.MeetingID)
+	assert.Equal(t, 28, files[2].Info.Revision)
+	assert.False(t, files[2].Card)
+
+	assert.Equal(t, "drop/c20180414cra5_337361.xml", files[3].Path)
+	require.NoError(t, files[3].Err)
+	assert.True(t, files[3].Card)
+	assert.Equal(t, MessageCard, files[3].Obj.Type)
+
+	assert.Equal(t, "drop/late/b2018041433736119270029.xml", files[4].Path)
+	var pathErr *fs.PathError
+	require.ErrorAs(t, files[4].Err, &pathErr)
+	assert.Equal(t, "drop/late/b2018041433736119270029.xml", pathErr.Path)
+	assert.Equal(t, 337361, files[4].Info.MeetingID)
+
+	assert.Equal(t, "drop/late/b2018041433736119270031.xml.gz", files[5].Path)
+	require.NoError(t, files[5].Err)
+	assert.Equal(t, expected, files[5].Obj)
+
+	_, err = ParseDir(fsys, "missing")
+	assert.Error(t, err)
+}
+
+func TestParseDirFeed(t *testing.T) {
+	files, err := ParseDir(os.DirFS("testdata"), "feed")
+	require.NoError(t, err)
+	require.Len(t, files, 5)
+	for _, f := range files {
+		assert.NoError(t, f.Err, f.Path)
+		assert.NotNil(t, f.Obj, f.Path)
+	}
+}
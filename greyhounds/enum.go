@@ -0,0 +1,253 @@
+package greyhounds
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// String returns human readable message type.
+func (s MessageType) String() string {
+	return string(s)
+}
+
+// String returns human readable card state.
+func (s CardState) String() string {
+	return string(s)
+}
+
+// String returns human readable meeting state.
+func (s MeetingState) String() string {
+	return string(s)
+}
+
+// String returns human readable race type.
+func (s RaceType) String() string {
+	return string(s)
+}
+
+// String returns human readable race state.
+func (s RaceState) String() string {
+	return string(s)
+}
+
+// String returns human readable trap seeding, "None" is returned if trap is
+// not seeded.
+func (s TrapSeeding) String() string {
+	if s == SeedingNone {
+		return "None"
+	}
+	return string(s)
+}
+
+// String returns human readable dog sex, e.g. "Bitch" for "b". Unknown codes
+// are returned as is.
+func (s DogSex) String() string {
+	switch s {
+	case SexDog:
+		return "Dog"
+	case SexBitch:
+		return "Bitch"
+	default:
+		return string(s)
+	}
+}
+
+// String returns human readable withdrawal reason, "None" is returned if no
+// reason is given.
+func (s WithdrawalReason) String() string {
+	if s == WithdrawalNone {
+		return "None"
+	}
+	return string(s)
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s MessageType) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *MessageType) UnmarshalText(text []byte) error {
+	v := MessageType(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid Message type value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s CardState) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *CardState) UnmarshalText(text []byte) error {
+	v := CardState(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid Card state value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s MeetingState) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *MeetingState) UnmarshalText(text []byte) error {
+	v := MeetingState(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid Meeting state value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s RaceType) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *RaceType) UnmarshalText(text []byte) error {
+	v := RaceType(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid Race type value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s RaceState) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *RaceState) UnmarshalText(text []byte) error {
+	v := RaceState(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid Race state value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s TrapSeeding) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *TrapSeeding) UnmarshalText(text []byte) error {
+	v := TrapSeeding(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid Trap seeding value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s DogSex) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *DogSex) UnmarshalText(text []byte) error {
+	v := DogSex(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid Dog sex value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s WithdrawalReason) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// other than WithdrawalUnknown are rejected.
+func (s *WithdrawalReason) UnmarshalText(text []byte) error {
+	v := WithdrawalReason(text)
+	if v != WithdrawalUnknown && !v.isValid() {
+		return fmt.Errorf("invalid Withdrawal reason value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+func (s CardState) isValid() bool {
+	switch s {
+	case CardAdvance,
+		CardFinal:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s DogSex) isValid() bool {
+	switch s {
+	case SexDog,
+		SexBitch:
+		return true
+	default:
+		return false
+	}
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface. Value is not
+// validated here, unknown values are reported by DogRacing unmarshaler or as
+// warnings in lenient mode.
+func (s *MessageType) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = MessageType(attr.Value)
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface, see
+// MessageType.UnmarshalXMLAttr.
+func (s *MeetingState) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = MeetingState(attr.Value)
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface, see
+// MessageType.UnmarshalXMLAttr.
+func (s *RaceType) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = RaceType(attr.Value)
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface, see
+// MessageType.UnmarshalXMLAttr.
+func (s *RaceState) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = RaceState(attr.Value)
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface, see
+// MessageType.UnmarshalXMLAttr.
+func (s *TrapSeeding) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = TrapSeeding(attr.Value)
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface, see
+// MessageType.UnmarshalXMLAttr.
+func (s *DogSex) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = DogSex(attr.Value)
+	return nil
+}
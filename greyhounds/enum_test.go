@@ -0,0 +1,71 @@
+package greyhounds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumString(t *testing.T) {
+	tests := []struct {
+		value    fmt.Stringer
+		expected string
+	}{
+		{value: MessageCard, expected: "Card"},
+		{value: CardAdvance, expected: "Advance"},
+		{value: MeetingAbandoned, expected: "Abandoned"},
+		{value: RaceTypeHurdles, expected: "Hurdles"},
+		{value: RaceGoingInTraps, expected: "Going in traps"},
+		{value: SeedingNone, expected: "None"},
+		{value: SeedingRails, expected: "Rails"},
+		{value: SexDog, expected: "Dog"},
+		{value: SexBitch, expected: "Bitch"},
+		{value: DogSex("x"), expected: "x"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.value.String())
+	}
+}
+
+func TestEnumStringXML(t *testing.T) {
+	// XML keeps feed codes
+	blob, err := xml.Marshal(xmlBreeding{Sex: SexBitch})
+	require.NoError(t, err)
+	assert.Contains(t, string(blob), `sex="b"`)
+}
+
+func TestEnumJSON(t *testing.T) {
+	in := struct {
+		State RaceState
+		Sex   DogSex
+	}{
+		State: RaceGoingInTraps,
+		Sex:   SexBitch,
+	}
+	blob, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"State":"Going in traps","Sex":"b"}`, string(blob))
+
+	out := in
+	out.State, out.Sex = "", ""
+	require.NoError(t, json.Unmarshal(blob, &out))
+	assert.Equal(t, in, out)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"State":"Running"}`), &out))
+	assert.Error(t, json.Unmarshal([]byte(`{"Sex":"x"}`), &out))
+}
+
+func TestEnumXMLUnknownValues(t *testing.T) {
+	var b xmlBreeding
+	require.NoError(t, xml.Unmarshal([]byte(`<Breeding sex="x"/>`), &b))
+	assert.Equal(t, DogSex("x"), b.Sex)
+
+	var r xmlFormRace
+	require.NoError(t, xml.Unmarshal([]byte(`<FormRace type="Chase"/>`), &r))
+	assert.Equal(t, RaceType("Chase"), r.Type)
+}
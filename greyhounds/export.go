@@ -0,0 +1,67 @@
+package greyhounds
+
+import "time"
+
+// RunnerRow is a flat, JSON friendly representation of a single dog together
+// with its meeting and race details. Field order defines JSON column order and
+// must not be changed.
+type RunnerRow struct {
+	MeetingID  int       `json:"meetingId"`  // The unique identifier for the meeting
+	Track      string    `json:"track"`      // The track at which the meeting is being held
+	Country    string    `json:"country"`    // The country where the track is located
+	Date       time.Time `json:"date"`       // The date when the meeting is started
+	RaceNumber int       `json:"raceNumber"` // The number of the race within the meeting
+	Time       time.Time `json:"time"`       // The time that the race is scheduled to start
+	TrapNo     int       `json:"trapNo"`     // The number of the trap the dog starts from
+	DogID      int       `json:"dogId"`      // The id number of the dog
+	DogName    string    `json:"dogName"`    // The name of the dog
+	Price      string    `json:"price"`      // Fractional starting price or latest show, empty if unknown
+}
+
+// RunnerRows returns a flat list of all dogs in the meeting, in race and trap
+// order. Vacant traps and traps having no dog are skipped.
+func (m *Meeting) RunnerRows() []RunnerRow {
+	var rows []RunnerRow
+	for _, r := range m.Races {
+		for _, t := range r.Traps {
+			if t.Vacant || t.Dog == nil {
+				continue
+			}
+			rows = append(rows, RunnerRow{
+				MeetingID:  m.MeetingID,
+				Track:      m.Track,
+				Country:    m.Country,
+				Date:       m.Date,
+				RaceNumber: r.RaceNumber,
+				Time:       r.Time,
+				TrapNo:     t.TrapNo,
+				DogID:      t.Dog.ID,
+				DogName:    t.Dog.Name,
+				Price:      t.latestPrice(),
+			})
+		}
+	}
+	return rows
+}
+
+// latestPrice returns current price of the dog formatted as a fraction, see
+// currentPrice.
+func (t *Trap) latestPrice() string {
+	if p := t.currentPrice(); p != nil {
+		return p.FractionalString()
+	}
+	return ""
+}
+
+// currentPrice returns starting price of the dog. If starting price is not
+// known yet the latest offered show of the first betting market is used
+// instead, see LatestShow. Nil is returned if dog has no price.
+func (t *Trap) currentPrice() *Price {
+	if t.Result != nil && t.Result.StartingPrice != nil {
+		return t.Result.StartingPrice
+	}
+	if s, ok := t.LatestShow(1); ok {
+		return s.Price
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package greyhounds
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeetingRunnerRows(t *testing.T) {
+	obj := parseTestFile(t, "testdata/Crayford/b201804143373611927.xml")
+	rows := obj.Meetings[0].RunnerRows()
+	require.Len(t, rows, 6)
+
+	blob, err := json.Marshal(rows[:2])
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+	  {"meetingId":337361,"track":"Crayford","country":"","date":"2018-04-14T00:00:00Z","raceNumber":1,"time":"2018-04-14T19:27:00+01:00","trapNo":1,"dogId":478812,"dogName":"Clonmannon Lady","price":"10/1"},
+	  {"meetingId":337361,"track":"Crayford","country":"","date":"2018-04-14T00:00:00Z","raceNumber":1,"time":"2018-04-14T19:27:00+01:00","trapNo":2,"dogId":497334,"dogName":"Kelva Matty","price":"5/2"}
+	]`, string(blob))
+
+	// Column order must be stable
+	blob, err = json.Marshal(rows[0])
+	require.NoError(t, err)
+	assert.Equal(t, `{"meetingId":337361,"track":"Crayford","country":"","date":"2018-04-14T00:00:00Z","raceNumber":1,"time":"2018-04-14T19:27:00+01:00","trapNo":1,"dogId":478812,"dogName":"Clonmannon Lady","price":"10/1"}`, string(blob))
+
+	// Vacant traps are skipped even if the feed lists a dog in them
+	vacant := Meeting{Races: []Race{{Traps: []Trap{
+		{TrapNo: 1, Dog: &Dog{ID: 1}},
+		{TrapNo: 2, Vacant: true, Dog: &Dog{ID: 2}},
+		{TrapNo: 3},
+	}}}}
+	rows = vacant.RunnerRows()
+	require.Len(t, rows, 1)
+	assert.Equal(t, 1, rows[0].TrapNo)
+
+	// Before the result starting price is unknown, latest show is used
+	obj = parseTestFile(t, "testdata/Crayford/b2018041433736119270007.xml")
+	assert.Equal(t, "6/1", obj.Meetings[0].RunnerRows()[0].Price)
+
+	// Export agrees with CurrentPrice, later shows of other markets and
+	// earlier shows out of feed order are ignored
+	at := time.Date(2018, 4, 14, 19, 0, 0, 0, time.UTC)
+	trap := Trap{Shows: []Show{
+		{TimeStamp: at, Price: &Price{Fractional: *big.NewRat(2, 1)}},
+		{TimeStamp: at.Add(time.Minute), MarketNumber: intPtr(2), Price: &Price{Fractional: *big.NewRat(5, 1)}},
+		{TimeStamp: at.Add(-time.Minute), Price: &Price{Fractional: *big.NewRat(3, 1)}},
+	}}
+	price, ok := trap.CurrentPrice(1)
+	require.True(t, ok)
+	assert.Equal(t, price.FractionalString(), trap.latestPrice())
+	assert.Equal(t, "2/1", trap.latestPrice())
+}
@@ -0,0 +1,75 @@
+package greyhounds
+
+import "encoding/json"
+
+// MarshalJSON implements json.Marshaler interface. Price is written as an
+// object holding decimal and fractional (e.g. "6/1") string representations.
+// Unknown representation is written as an empty string.
+func (p Price) MarshalJSON() ([]byte, error) {
+	data := struct {
+		Decimal    string
+		Fractional string
+	}{
+		Decimal: formatDecimal(p.Decimal),
+	}
+	if p.Fractional.Sign() != 0 {
+		data.Fractional = p.Fractional.String()
+	}
+	return json.Marshal(data)
+}
+
+// MarshalJSON implements json.Marshaler interface. WinTime is written in
+// seconds.
+func (r Race) MarshalJSON() ([]byte, error) {
+	type race Race
+	return json.Marshal(struct {
+		race
+		WinTime float64
+	}{
+		race:    race(r),
+		WinTime: r.WinTime.Seconds(),
+	})
+}
+
+// MarshalJSON implements json.Marshaler interface. AdjustedTime is written in
+// seconds.
+func (t BestTime) MarshalJSON() ([]byte, error) {
+	type bestTime BestTime
+	return json.Marshal(struct {
+		bestTime
+		AdjustedTime float64
+	}{
+		bestTime:     bestTime(t),
+		AdjustedTime: t.AdjustedTime.Seconds(),
+	})
+}
+
+// MarshalJSON implements json.Marshaler interface. WinningTime is written in
+// seconds.
+func (r FormRace) MarshalJSON() ([]byte, error) {
+	type formRace FormRace
+	return json.Marshal(struct {
+		formRace
+		WinningTime float64
+	}{
+		formRace:    formRace(r),
+		WinningTime: r.WinningTime.Seconds(),
+	})
+}
+
+// MarshalJSON implements json.Marshaler interface. SectionalTime, RunTime and
+// AdjustedTime are written in seconds.
+func (r Result) MarshalJSON() ([]byte, error) {
+	type result Result
+	return json.Marshal(struct {
+		result
+		SectionalTime float64
+		RunTime       float64
+		AdjustedTime  float64
+	}{
+		result:        result(r),
+		SectionalTime: r.SectionalTime.Seconds(),
+		RunTime:       r.RunTime.Seconds(),
+		AdjustedTime:  r.AdjustedTime.Seconds(),
+	})
+}
@@ -0,0 +1,71 @@
+package greyhounds
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestMarshalJSONGolden(t *testing.T) {
+	tests := []struct {
+		file   string
+		golden string
+	}{
+		{
+			file:   "testdata/Crayford/b201804143373611927.xml",
+			golden: "testdata/golden/b201804143373611927.json",
+		},
+	}
+
+	for _, test := range tests {
+		obj := parseTestFile(t, test.file)
+		blob, err := json.MarshalIndent(obj, "", "  ")
+		require.NoError(t, err, test.file)
+		if *update {
+			require.NoError(t, ioutil.WriteFile(test.golden, blob, 0644))
+		}
+		expected, err := ioutil.ReadFile(test.golden)
+		require.NoError(t, err, test.golden)
+		assert.Equal(t, string(expected), string(blob), test.golden)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	tests := []struct {
+		obj  interface{}
+		json string
+	}{
+		{
+			obj:  Price{Decimal: makeDecimal(t, "7.00"), Fractional: *big.NewRat(6, 1)},
+			json: `{"Decimal":"7.00","Fractional":"6/1"}`,
+		},
+		{
+			obj:  Price{},
+			json: `{"Decimal":"","Fractional":""}`,
+		},
+		{
+			obj: BestTime{
+				AdjustedTime: time.Second*28 + time.Millisecond*500,
+				Date:         makeTime(t, "2018-04-01T00:00:00Z"),
+				RaceNumber:   3,
+				MeetingID:    337361,
+				Class:        "A5",
+			},
+			json: `{"Date":"2018-04-01T00:00:00Z","RaceNumber":3,"MeetingID":337361,"Class":"A5","AdjustedTime":28.5}`,
+		},
+	}
+
+	for _, test := range tests {
+		blob, err := json.Marshal(test.obj)
+		require.NoError(t, err)
+		assert.JSONEq(t, test.json, string(blob))
+	}
+}
@@ -0,0 +1,613 @@
+package greyhounds
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/advbet/decimal"
+)
+
+// MarshalXML implements xml.Marshaler interface. Produced document re-parses
+// to an equal DogRacing object.
+func (r DogRacing) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	var meetings []xmlMeeting
+	for _, m := range r.Meetings {
+		meetings = append(meetings, xmlMeeting(m))
+	}
+	data := struct {
+		Type  MessageType `xml:"type,attr"`
+		State string      `xml:"state,attr,omitempty"`
+
+		Meetings []xmlMeeting `xml:"Meeting"`
+	}{
+		Type:     r.Type,
+		State:    r.State,
+		Meetings: meetings,
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (m xmlMeeting) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type reserveDogs struct {
+		Dogs []xmlDog `xml:"Dog"`
+	}
+	var races []xmlRace
+	for _, r := range m.Races {
+		races = append(races, xmlRace(r))
+	}
+	var reserve *reserveDogs
+	if len(m.ReserveDogs) > 0 {
+		reserve = &reserveDogs{}
+		for _, d := range m.ReserveDogs {
+			reserve.Dogs = append(reserve.Dogs, xmlDog(d))
+		}
+	}
+	data := struct {
+		MeetingID int          `xml:"meetingId,attr"`
+		Track     string       `xml:"track,attr"`
+		Country   string       `xml:"country,attr,omitempty"`
+		Date      string       `xml:"date,attr,omitempty"`
+		State     MeetingState `xml:"state,attr"`
+
+		Races       []xmlRace    `xml:"Race"`
+		ReserveDogs *reserveDogs `xml:"ReserveDogs"`
+	}{
+		MeetingID:   m.MeetingID,
+		Track:       m.Track,
+		Country:     m.Country,
+		Date:        formatDate(m.Date),
+		State:       m.State,
+		Races:       races,
+		ReserveDogs: reserve,
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (r xmlRace) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type comments struct {
+		Comments []xmlComment `xml:"Comment"`
+	}
+	var cs *comments
+	if len(r.Comments) > 0 {
+		cs = &comments{}
+		for _, c := range r.Comments {
+			cs.Comments = append(cs.Comments, xmlComment(c))
+		}
+	}
+	var traps []xmlTrap
+	for _, t := range r.Traps {
+		traps = append(traps, xmlTrap(t))
+	}
+	var nonRunners []xmlNonRunner
+	for _, nr := range r.NonRunners {
+		nonRunners = append(nonRunners, xmlNonRunner(nr))
+	}
+	data := struct {
+		Revision   int       `xml:"revision,attr,omitempty"`
+		RaceNumber int       `xml:"raceNumber,attr"`
+		Time       string    `xml:"time,attr,omitempty"`
+		Type       RaceType  `xml:"type,attr"`
+		Handicap   xmlYesNo  `xml:"handicap,attr"`
+		Class      string    `xml:"class,attr,omitempty"`
+		Distance   int       `xml:"distance,attr,omitempty"`
+		Title      string    `xml:"title,attr,omitempty"`
+		Prizes     string    `xml:"prizes,attr,omitempty"`
+		OffTime    string    `xml:"offTime,attr,omitempty"`
+		Going      string    `xml:"going,attr,omitempty"`
+		WinTime    string    `xml:"winTime,attr,omitempty"`
+		State      RaceState `xml:"state,attr"`
+		Bags       xmlYesNo  `xml:"Bags,attr"`
+		Tricast    xmlYesNo  `xml:"tricast,attr"`
+
+		Comments   *comments      `xml:"Comments"`
+		Traps      []xmlTrap      `xml:"Trap"`
+		NonRunners []xmlNonRunner `xml:"NonRunner"`
+		Dividends  *xmlDividends  `xml:"Dividends"`
+	}{
+		Revision:   r.Revision,
+		RaceNumber: r.RaceNumber,
+		Time:       formatTime(r.Time),
+		Type:       r.Type,
+		Handicap:   xmlYesNo(r.Handicap),
+		Class:      r.Class,
+		Distance:   r.Distance,
+		Title:      r.Title,
+		Prizes:     r.Prizes,
+		Going:      r.Going,
+		WinTime:    FormatDuration(r.WinTime),
+		State:      r.State,
+		Bags:       xmlYesNo(r.Bags),
+		Tricast:    xmlYesNo(r.Tricast),
+		Comments:   cs,
+		Traps:      traps,
+		NonRunners: nonRunners,
+		Dividends:  (*xmlDividends)(r.Dividends),
+	}
+	if r.OffTime != nil {
+		data.OffTime = formatTime(*r.OffTime)
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (t xmlTrap) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	var shows []xmlShow
+	for _, s := range t.Shows {
+		shows = append(shows, xmlShow(s))
+	}
+	data := struct {
+		TrapNo   int         `xml:"trap,attr"`
+		Vacant   xmlYesNo    `xml:"vacant,attr"`
+		Wide     xmlYesNo    `xml:"wide,attr"`
+		Seeding  TrapSeeding `xml:"seeding,attr,omitempty"`
+		Handicap string      `xml:"handicap,attr,omitempty"`
+		Reserve  xmlYesNo    `xml:"reserve,attr"`
+		Photo    int         `xml:"photo,attr,omitempty"`
+
+		Dog    *xmlDog    `xml:"Dog"`
+		Shows  []xmlShow  `xml:"Show"`
+		Result *xmlResult `xml:"Result"`
+	}{
+		TrapNo:   t.TrapNo,
+		Vacant:   xmlYesNo(t.Vacant),
+		Wide:     xmlYesNo(t.Wide),
+		Seeding:  t.Seeding,
+		Handicap: t.Handicap,
+		Reserve:  xmlYesNo(t.Reserve),
+		Photo:    t.Photo,
+		Dog:      (*xmlDog)(t.Dog),
+		Shows:    shows,
+		Result:   (*xmlResult)(t.Result),
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (d xmlDog) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type expectedTimes struct {
+		ExpectedTimes []xmlExpectedTime `xml:"ExpectedTime"`
+	}
+	type forecastPrice struct {
+		Source string    `xml:"source,attr,omitempty"`
+		Price  *xmlPrice `xml:"Price"`
+	}
+	type form struct {
+		FormRaces []xmlFormRace `xml:"FormRace"`
+	}
+	var times *expectedTimes
+	if len(d.ExpectedTimes) > 0 {
+		times = &expectedTimes{}
+		for _, t := range d.ExpectedTimes {
+			times.ExpectedTimes = append(times.ExpectedTimes, xmlExpectedTime(t))
+		}
+	}
+	var ratings []xmlRating
+	for _, r := range d.Ratings {
+		ratings = append(ratings, xmlRating(r))
+	}
+	var comments []xmlComment
+	for _, c := range d.Comments {
+		comments = append(comments, xmlComment(c))
+	}
+	var forecast *forecastPrice
+	if d.ForecastPriceSource != "" || d.ForecastPrice != nil {
+		forecast = &forecastPrice{
+			Source: d.ForecastPriceSource,
+			Price:  (*xmlPrice)(d.ForecastPrice),
+		}
+	}
+	var trainer *xmlTrainer
+	if d.Trainer != (Trainer{}) {
+		trainer = (*xmlTrainer)(&d.Trainer)
+	}
+	var owner *xmlOwner
+	if d.Owner != (Owner{}) {
+		owner = (*xmlOwner)(&d.Owner)
+	}
+	var formRaces *form
+	if len(d.FormRaces) > 0 {
+		formRaces = &form{}
+		for _, r := range d.FormRaces {
+			formRaces.FormRaces = append(formRaces.FormRaces, xmlFormRace(r))
+		}
+	}
+	data := struct {
+		ID     int    `xml:"id,attr"`
+		Name   string `xml:"name,attr"`
+		Origin string `xml:"origin,attr,omitempty"`
+
+		BestTime      *xmlBestTime   `xml:"BestTime"`
+		ExpectedTimes *expectedTimes `xml:"ExpectedTimes"`
+		Breeding      *xmlBreeding   `xml:"Breeding"`
+		Trainer       *xmlTrainer    `xml:"Trainer"`
+		Owner         *xmlOwner      `xml:"Owner"`
+		Ratings       []xmlRating    `xml:"Rating"`
+		Comments      []xmlComment   `xml:"Comment"`
+		ForecastPrice *forecastPrice `xml:"ForecastPrice"`
+		Form          *form          `xml:"Form"`
+	}{
+		ID:            d.ID,
+		Name:          d.Name,
+		Origin:        d.Origin,
+		BestTime:      (*xmlBestTime)(d.BestTime),
+		ExpectedTimes: times,
+		Breeding:      (*xmlBreeding)(d.Breeding),
+		Trainer:       trainer,
+		Owner:         owner,
+		Ratings:       ratings,
+		Comments:      comments,
+		ForecastPrice: forecast,
+		Form:          formRaces,
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface. Comment text is written as
+// escaped character data.
+func (c xmlComment) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	data := struct {
+		Source  string `xml:"source,attr,omitempty"`
+		Type    string `xml:"type,attr,omitempty"`
+		Comment string `xml:",chardata"`
+	}{
+		Source:  c.Source,
+		Type:    c.Type,
+		Comment: c.Text,
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (nr xmlNonRunner) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	data := struct {
+		Trap   int     `xml:"trap,attr"`
+		Reason string  `xml:"reasonForWithdrawal,attr,omitempty"`
+		Dog    *xmlDog `xml:"Dog"`
+	}{
+		Trap:   nr.Trap,
+		Reason: string(nr.Reason),
+		Dog:    (*xmlDog)(nr.Dog),
+	}
+	if nr.Reason == WithdrawalUnknown {
+		data.Reason = nr.ReasonText
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (t xmlBestTime) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	data := struct {
+		AdjustedTime string `xml:"adjustedTime,attr,omitempty"`
+		Date         string `xml:"date,attr,omitempty"`
+		RaceNumber   int    `xml:"raceNumber,attr,omitempty"`
+		MeetingID    int    `xml:"meetingId,attr,omitempty"`
+		Class        string `xml:"class,attr,omitempty"`
+	}{
+		AdjustedTime: FormatDuration(t.AdjustedTime),
+		Date:         formatDate(t.Date),
+		RaceNumber:   t.RaceNumber,
+		MeetingID:    t.MeetingID,
+		Class:        t.Class,
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (b xmlBreeding) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	data := struct {
+		Sire   string `xml:"sire,attr,omitempty"`
+		Dam    string `xml:"dam,attr,omitempty"`
+		Born   string `xml:"born,attr,omitempty"`
+		Colour string `xml:"colour,attr,omitempty"`
+		Sex    DogSex `xml:"sex,attr,omitempty"`
+		Season string `xml:"season,attr,omitempty"`
+	}{
+		Sire:   b.Sire,
+		Dam:    b.Dam,
+		Born:   formatDate(b.Born),
+		Colour: b.Colour,
+		Sex:    b.Sex,
+		Season: b.Season,
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (t xmlTrainer) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	data := struct {
+		ID    int    `xml:"id,attr,omitempty"`
+		Name  string `xml:"name,attr,omitempty"`
+		Track string `xml:"track,attr,omitempty"`
+	}{
+		ID:    t.ID,
+		Name:  t.Name,
+		Track: t.Track,
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (o xmlOwner) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	data := struct {
+		ID   int    `xml:"id,attr,omitempty"`
+		Name string `xml:"name,attr,omitempty"`
+	}{
+		ID:   o.ID,
+		Name: o.Name,
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (r xmlRating) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	data := struct {
+		Source string `xml:"source,attr,omitempty"`
+		Type   string `xml:"type,attr,omitempty"`
+		Value  string `xml:"value,attr,omitempty"`
+	}{
+		Source: r.Source,
+		Type:   r.Type,
+		Value:  r.Value,
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (t xmlExpectedTime) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	data := struct {
+		Source string `xml:"source,attr,omitempty"`
+		Type   string `xml:"type,attr,omitempty"`
+		Value  string `xml:"value,attr,omitempty"`
+	}{
+		Source: t.Source,
+		Type:   t.Type,
+		Value:  t.Value,
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface. Race date and start time are
+// written as separate attributes, start time includes the date.
+func (r xmlFormRace) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	var traps []xmlFormTrap
+	for _, t := range r.FormTraps {
+		traps = append(traps, xmlFormTrap(t))
+	}
+	data := struct {
+		MeetingID   int      `xml:"meetingId,attr,omitempty"`
+		Track       string   `xml:"track,attr,omitempty"`
+		Date        string   `xml:"date,attr,omitempty"`
+		RaceNumber  int      `xml:"raceNumber,attr,omitempty"`
+		Going       string   `xml:"going,attr,omitempty"`
+		Time        string   `xml:"time,attr,omitempty"`
+		Type        RaceType `xml:"type,attr,omitempty"`
+		Class       string   `xml:"class,attr,omitempty"`
+		Distance    int      `xml:"distance,attr,omitempty"`
+		WinningTime string   `xml:"winningTime,attr,omitempty"`
+
+		FormTraps []xmlFormTrap `xml:"FormTrap"`
+	}{
+		MeetingID:   r.MeetingID,
+		Track:       r.Track,
+		Date:        formatDate(r.Date),
+		RaceNumber:  r.RaceNumber,
+		Going:       r.Going,
+		Time:        formatTime(r.Date),
+		Type:        r.Type,
+		Class:       r.Class,
+		Distance:    r.Distance,
+		WinningTime: FormatDuration(r.WinningTime),
+		FormTraps:   traps,
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (t xmlFormTrap) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	data := struct {
+		Trap     int         `xml:"trap,attr"`
+		Wide     xmlYesNo    `xml:"wide,attr"`
+		Seeding  TrapSeeding `xml:"seeding,attr,omitempty"`
+		Handicap string      `xml:"handicap,attr,omitempty"`
+		Dog      *xmlDog     `xml:"Dog"`
+		Result   *xmlResult  `xml:"Result"`
+	}{
+		Trap:     t.Trap,
+		Wide:     xmlYesNo(t.Wide),
+		Seeding:  t.Seeding,
+		Handicap: t.Handicap,
+		Dog:      (*xmlDog)(t.Dog),
+		Result:   (*xmlResult)(t.Result),
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (r xmlResult) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type startingPrice struct {
+		MarketPos int `xml:"marketPos,attr,omitempty"`
+		MarketCnt int `xml:"marketCnt,attr,omitempty"`
+
+		Price *xmlPrice `xml:"Price"`
+	}
+	var sp *startingPrice
+	if r.MarketPosition != 0 || r.MarketCount != 0 || r.StartingPrice != nil {
+		sp = &startingPrice{
+			MarketPos: r.MarketPosition,
+			MarketCnt: r.MarketCount,
+			Price:     (*xmlPrice)(r.StartingPrice),
+		}
+	}
+	data := struct {
+		Position      string  `xml:"position,attr,omitempty"`
+		BtnDistance   string  `xml:"btnDistance,attr,omitempty"`
+		SectionalTime string  `xml:"sectionalTime,attr,omitempty"`
+		BendPosition  string  `xml:"bendPosition,attr,omitempty"`
+		RunComment    string  `xml:"runComment,attr,omitempty"`
+		RunTime       string  `xml:"runTime,attr,omitempty"`
+		Weight        float64 `xml:"weight,attr,omitempty"`
+		AdjustedTime  string  `xml:"adjustedTime,attr,omitempty"`
+
+		StartingPrice *startingPrice `xml:"StartingPrice"`
+	}{
+		Position:      r.Position,
+		BtnDistance:   r.BtnDistance,
+		SectionalTime: FormatDuration(r.SectionalTime),
+		BendPosition:  r.BendPosition,
+		RunComment:    r.RunComment,
+		RunTime:       FormatDuration(r.RunTime),
+		Weight:        r.Weight,
+		AdjustedTime:  FormatDuration(r.AdjustedTime),
+		StartingPrice: sp,
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (s xmlShow) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	data := struct {
+		TimeStamp    string   `xml:"timeStamp,attr,omitempty"`
+		MarketNumber *int     `xml:"marketNumber,attr,omitempty"`
+		NoOffers     xmlYesNo `xml:"noOffers,attr,omitempty"`
+
+		Price *xmlPrice `xml:"Price"`
+	}{
+		TimeStamp:    formatTime(s.TimeStamp),
+		MarketNumber: s.MarketNumber,
+		NoOffers:     xmlYesNo(s.NoOffers),
+		Price:        (*xmlPrice)(s.Price),
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface. Fractional part is written
+// only for non zero prices.
+func (p xmlPrice) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	data := struct {
+		Decimal     string `xml:"decimal,attr,omitempty"`
+		Numerator   int64  `xml:"numerator,attr,omitempty"`
+		Denominator int64  `xml:"denominator,attr,omitempty"`
+	}{
+		Decimal: formatDecimal(p.Decimal),
+	}
+	if p.Fractional.Sign() != 0 {
+		data.Numerator = p.Fractional.Num().Int64()
+		data.Denominator = p.Fractional.Denom().Int64()
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (d xmlDividends) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	var forecast []xmlForecast
+	for _, f := range d.Forecast {
+		forecast = append(forecast, xmlForecast(f))
+	}
+	var tricast []xmlTricast
+	for _, t := range d.Tricast {
+		tricast = append(tricast, xmlTricast(t))
+	}
+	data := struct {
+		Forecast []xmlForecast `xml:"Forecast"`
+		Tricast  []xmlTricast  `xml:"Tricast"`
+	}{
+		Forecast: forecast,
+		Tricast:  tricast,
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (f xmlForecast) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	data := struct {
+		Trap1    int    `xml:"trap1,attr"`
+		Trap2    int    `xml:"trap2,attr"`
+		Dividend string `xml:"dividend,attr,omitempty"`
+	}{
+		Trap1:    f.Trap1,
+		Trap2:    f.Trap2,
+		Dividend: formatDecimal(f.Dividend),
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXML implements xml.Marshaler interface.
+func (t xmlTricast) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	data := struct {
+		Trap1    int    `xml:"trap1,attr"`
+		Trap2    int    `xml:"trap2,attr"`
+		Trap3    int    `xml:"trap3,attr"`
+		Dividend string `xml:"dividend,attr,omitempty"`
+	}{
+		Trap1:    t.Trap1,
+		Trap2:    t.Trap2,
+		Trap3:    t.Trap3,
+		Dividend: formatDecimal(t.Dividend),
+	}
+	return e.EncodeElement(data, start)
+}
+
+// MarshalXMLAttr implements xml.MarshalerAttr interface.
+func (b xmlYesNo) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if b {
+		return xml.Attr{Name: name, Value: "Yes"}, nil
+	}
+	return xml.Attr{Name: name, Value: "No"}, nil
+}
+
+// formatDate converts date to ISO 8601:1988 yyyymmdd formatted string. Empty
+// string is returned for zero time.
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("20060102")
+}
+
+// formatTime converts time to ISO 8601:1988 yyyymmddThhmmss+/-hhmm formatted
+// string. Empty string is returned for zero time.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("20060102T150405-0700")
+}
+
+// FormatDuration is the reverse of parseDuration, it converts duration to ISO
+// 8601:1988 hhmmss.sss formatted string, leading zero hours and minutes are
+// omitted. Duration is truncated to milliseconds, empty string is returned for
+// zero duration.
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	hour := d / time.Hour
+	mins := d % time.Hour / time.Minute
+	secs := d % time.Minute / time.Second
+	mils := d % time.Second / time.Millisecond
+	switch {
+	case hour > 0:
+		return fmt.Sprintf("%s%02d%02d%02d.%03d", sign, hour, mins, secs, mils)
+	case mins > 0:
+		return fmt.Sprintf("%s%02d%02d.%03d", sign, mins, secs, mils)
+	default:
+		return fmt.Sprintf("%s%02d.%03d", sign, secs, mils)
+	}
+}
+
+// formatDecimal returns string representation of decimal number. Empty string
+// is returned for an unset value.
+func formatDecimal(n decimal.Number) string {
+	if n == (decimal.Number{}) {
+		return ""
+	}
+	return n.String()
+}
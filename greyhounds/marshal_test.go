@@ -0,0 +1,74 @@
+package greyhounds
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	dirs := []string{
+		"testdata/Crayford",
+		"testdata/Nottingham",
+		"testdata/Perry Barr",
+		"testdata/The Meadows",
+		"testdata/Wheeling Island",
+		"testdata/feed",
+	}
+
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		require.NoError(t, err, dir)
+		for _, f := range files {
+			path := path.Join(dir, f.Name())
+			t.Log(fmt.Sprintf("checking: %s", path))
+			obj := parseTestFile(t, path)
+
+			blob, err := MarshalFile(obj)
+			require.NoError(t, err, path)
+			again, err := ParseFile(blob)
+			require.NoError(t, err, path)
+			assert.Equal(t, obj, again, path)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d time.Duration
+		s string
+	}{
+		{
+			d: 0,
+			s: "",
+		},
+		{
+			d: time.Second*23 + time.Millisecond*900,
+			s: "23.900",
+		},
+		{
+			d: -(time.Second + time.Millisecond*230),
+			s: "-01.230",
+		},
+		{
+			d: time.Minute + time.Second*2 + time.Millisecond*3,
+			s: "0102.003",
+		},
+		{
+			d: time.Hour,
+			s: "010000.000",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.s, FormatDuration(test.d), test.d.String())
+		d, err := parseDuration(test.s)
+		require.NoError(t, err, test.s)
+		assert.Equal(t, test.d, d, test.s)
+	}
+}
@@ -0,0 +1,166 @@
+package greyhounds
+
+import "sort"
+
+// MergeRace combines two states of the same race received in different
+// documents. Fields of the race with the higher revision are used (new wins
+// when revisions are equal), sub objects omitted by it are preserved from the
+// other race:
+//
+//   - traps are matched by trap number, traps missing from the higher revision
+//     are kept;
+//   - trap dog and result are taken from the higher revision unless absent
+//     there;
+//   - trap shows are accumulated from both races, shows with the same time
+//     stamp and market number are not repeated, shows are ordered by time
+//     stamp;
+//   - comments, non runners and dividends are taken from the higher revision
+//     unless absent there.
+func MergeRace(old, new Race) Race {
+	hi, lo := new, old
+	if old.Revision > new.Revision {
+		hi, lo = old, new
+	}
+
+	merged := hi
+	merged.Traps = mergeTraps(hi.Traps, lo.Traps)
+	if len(merged.Comments) == 0 {
+		merged.Comments = lo.Comments
+	}
+	if len(merged.NonRunners) == 0 {
+		merged.NonRunners = lo.NonRunners
+	}
+	if merged.Dividends == nil {
+		merged.Dividends = lo.Dividends
+	}
+	return merged
+}
+
+// mergeTraps merges traps of the higher revision race hi with the traps of the
+// lower revision race lo.
+func mergeTraps(hi, lo []Trap) []Trap {
+	traps := make([]Trap, 0, len(hi)+len(lo))
+	traps = append(traps, hi...)
+	for _, old := range lo {
+		found := false
+		for i := range traps {
+			if traps[i].TrapNo != old.TrapNo {
+				continue
+			}
+			found = true
+			if traps[i].Dog == nil {
+				traps[i].Dog = old.Dog
+			}
+			if traps[i].Result == nil {
+				traps[i].Result = old.Result
+			}
+			traps[i].Shows = mergeShows(traps[i].Shows, old.Shows)
+			break
+		}
+		if !found {
+			traps = append(traps, old)
+		}
+	}
+	sort.SliceStable(traps, func(i, j int) bool {
+		return traps[i].TrapNo < traps[j].TrapNo
+	})
+	return traps
+}
+
+// mergeShows returns shows of hi extended with the shows of lo that are not
+// present in hi.
+func mergeShows(hi, lo []Show) []Show {
+	if len(lo) == 0 {
+		return hi
+	}
+	shows := make([]Show, 0, len(hi)+len(lo))
+	shows = append(shows, hi...)
+	for _, old := range lo {
+		found := false
+		for _, s := range hi {
+			if s.TimeStamp.Equal(old.TimeStamp) && s.Market() == old.Market() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			shows = append(shows, old)
+		}
+	}
+	sort.SliceStable(shows, func(i, j int) bool {
+		return shows[i].TimeStamp.Before(shows[j].TimeStamp)
+	})
+	return shows
+}
+
+// MergeMeeting combines two states of the same meeting received in different
+// documents. Races are matched by race number and combined using MergeRace,
+// races missing from the new meeting are kept. Meeting attributes are taken
+// from the new meeting unless all of its races are older than the known ones.
+func MergeMeeting(old, new Meeting) Meeting {
+	merged := old
+	merged.Races = append([]Race(nil), old.Races...)
+
+	// document is stale if all of its races are older than the known ones
+	stale := len(new.Races) > 0
+	for _, race := range new.Races {
+		found := false
+		for i := range merged.Races {
+			if merged.Races[i].RaceNumber != race.RaceNumber {
+				continue
+			}
+			found = true
+			if merged.Races[i].Revision <= race.Revision {
+				stale = false
+			}
+			merged.Races[i] = MergeRace(merged.Races[i], race)
+			break
+		}
+		if !found {
+			merged.Races = append(merged.Races, race)
+			stale = false
+		}
+	}
+	sort.SliceStable(merged.Races, func(i, j int) bool {
+		return merged.Races[i].RaceNumber < merged.Races[j].RaceNumber
+	})
+	if stale {
+		return merged
+	}
+
+	merged.MeetingID = new.MeetingID
+	if new.Track != "" {
+		merged.Track = new.Track
+	}
+	if new.Country != "" {
+		merged.Country = new.Country
+	}
+	if !new.Date.IsZero() {
+		merged.Date = new.Date
+	}
+	merged.State = new.State
+	if len(new.ReserveDogs) > 0 {
+		merged.ReserveDogs = new.ReserveDogs
+	}
+	return merged
+}
+
+// Merge updates meeting with the state received in a later document for the
+// same meeting, see MergeMeeting for the merge rules.
+func (m *Meeting) Merge(other Meeting) {
+	*m = MergeMeeting(*m, other)
+}
+
+// MergeInto folds meetings of the document into a store of meetings keyed by
+// meeting ID. Meetings already in the store are updated using Meeting.Merge,
+// new meetings are added to the store.
+func (r *DogRacing) MergeInto(store map[int]*Meeting) {
+	for _, m := range r.Meetings {
+		if known, ok := store[m.MeetingID]; ok {
+			known.Merge(m)
+			continue
+		}
+		meeting := m
+		store[m.MeetingID] = &meeting
+	}
+}
@@ -0,0 +1,91 @@
+package greyhounds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDogRacingMergeInto(t *testing.T) {
+	store := make(map[int]*Meeting)
+	for _, file := range []string{
+		"testdata/Crayford/c20180414cra5_337361.xml",
+		"testdata/Crayford/b2018041433736119270007.xml",
+		"testdata/Crayford/b2018041433736119270028.xml",
+		"testdata/Crayford/b201804143373611943.xml",
+		// lower revision received out of order must be ignored
+		"testdata/Crayford/b2018041433736119270001.xml",
+	} {
+		parseTestFile(t, file).MergeInto(store)
+	}
+
+	require.Len(t, store, 1)
+	m := store[337361]
+	require.NotNil(t, m)
+	assert.Equal(t, "Crayford", m.Track)
+	assert.Equal(t, MeetingActive, m.State)
+	require.Len(t, m.Races, 12)
+
+	assert.Equal(t, 1, m.Races[0].RaceNumber)
+	assert.Equal(t, 28, m.Races[0].Revision)
+	assert.Equal(t, RaceFinalResult, m.Races[0].State)
+
+	assert.Equal(t, 2, m.Races[1].RaceNumber)
+	assert.Equal(t, RaceFinalResult, m.Races[1].State)
+
+	assert.Equal(t, 3, m.Races[2].RaceNumber)
+	assert.Equal(t, RaceDormant, m.Races[2].State)
+}
+
+func testRace(t *testing.T, file string) Race {
+	dr := parseTestFile(t, file)
+	require.Len(t, dr.Meetings, 1)
+	require.Len(t, dr.Meetings[0].Races, 1)
+	return dr.Meetings[0].Races[0]
+}
+
+func countShows(r Race) int {
+	n := 0
+	for _, trap := range r.Traps {
+		n += len(trap.Shows)
+	}
+	return n
+}
+
+func TestMergeRace(t *testing.T) {
+	early := testRace(t, "testdata/Crayford/b2018041433736119270007.xml")
+	final := testRace(t, "testdata/Crayford/b2018041433736119270028.xml")
+	require.NotNil(t, final.Dividends)
+
+	// lower revision received out of order does not override
+	merged := MergeRace(final, early)
+	assert.Equal(t, 28, merged.Revision)
+	assert.Equal(t, final, merged)
+
+	// later message omitting results, dividends and show history
+	later := testRace(t, "testdata/Crayford/b2018041433736119270028.xml")
+	later.Revision = 29
+	later.Dividends = nil
+	for i := range later.Traps {
+		later.Traps[i].Result = nil
+		if n := len(later.Traps[i].Shows); n > 0 {
+			later.Traps[i].Shows = later.Traps[i].Shows[n-1:]
+		}
+	}
+	merged = MergeRace(final, later)
+	assert.Equal(t, 29, merged.Revision)
+	assert.Equal(t, final.Dividends, merged.Dividends)
+	assert.Equal(t, countShows(final), countShows(merged))
+	for i := range merged.Traps {
+		assert.Equal(t, final.Traps[i].Result, merged.Traps[i].Result)
+		assert.Equal(t, final.Traps[i].Shows, merged.Traps[i].Shows)
+	}
+
+	// show history is accumulated
+	early.Revision = 30
+	merged = MergeRace(final, early)
+	assert.Equal(t, 30, merged.Revision)
+	assert.Equal(t, countShows(final), countShows(merged))
+	assert.Equal(t, final.Dividends, merged.Dividends)
+}
@@ -0,0 +1,124 @@
+package greyhounds
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/advbet/decimal"
+)
+
+// oddsExp is the exponent of decimal odds derived from fractional prices,
+// decimal odds are rounded to two decimal places.
+const oddsExp = -2
+
+// DecimalOdds returns decimal (European) odds of the price, stake included.
+// Decimal price sent by PA is in HK format (stake excluded) and takes
+// precedence, otherwise odds are derived from the fractional price and rounded
+// to two decimal places, e.g. evens (1/1) is 2.00. Error is returned if price
+// is empty.
+func (p *Price) DecimalOdds() (decimal.Number, error) {
+	if !p.Decimal.IsZero() {
+		return p.Decimal.Add(decimal.FromInt(1)), nil
+	}
+	if p.Fractional.Sign() <= 0 {
+		return decimal.Number{}, fmt.Errorf("price is empty")
+	}
+	odds := new(big.Rat).Add(&p.Fractional, big.NewRat(1, 1))
+	// round half up to the odds exponent
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(-oddsExp), nil)
+	num := new(big.Int).Mul(odds.Num(), scale)
+	num.Mul(num, big.NewInt(2))
+	num.Add(num, odds.Denom())
+	num.Quo(num, new(big.Int).Mul(odds.Denom(), big.NewInt(2)))
+	return decimal.New(num.Int64(), oddsExp), nil
+}
+
+// FractionalString returns fractional representation of the price, e.g. "6/1"
+// or "1/1" for evens. If only HK decimal price is known it is approximated by a
+// fraction, see Fraction. Empty string is returned if price is empty.
+func (p *Price) FractionalString() string {
+	if f := p.Fraction(); f != nil {
+		return f.String()
+	}
+	return ""
+}
+
+// maxFractionDenominator limits denominators of fractions approximated from
+// decimal prices.
+const maxFractionDenominator = 100
+
+// fractionTolerance is the precision of decimal prices sent by PA, they have
+// three decimal places.
+var fractionTolerance = big.NewRat(1, 2000)
+
+// Fraction returns fractional representation of the price. If only HK decimal
+// price is known (e.g. Australian tracks) it is approximated by the simplest
+// fraction matching decimal price to three decimal places, e.g. 3.333 is 10/3.
+// Nil is returned if price is empty.
+func (p *Price) Fraction() *big.Rat {
+	if p.Fractional.Sign() > 0 {
+		return new(big.Rat).Set(&p.Fractional)
+	}
+	if p.Decimal.IsZero() || p.Decimal.Rat().Sign() < 0 {
+		return nil
+	}
+	x := p.Decimal.Rat()
+	// continued fraction convergents h/k of x
+	h0, h1 := big.NewInt(0), big.NewInt(1)
+	k0, k1 := big.NewInt(1), big.NewInt(0)
+	r := new(big.Rat).Set(x)
+	var best *big.Rat
+	for {
+		a := new(big.Int).Quo(r.Num(), r.Denom())
+		h := new(big.Int).Add(new(big.Int).Mul(a, h1), h0)
+		k := new(big.Int).Add(new(big.Int).Mul(a, k1), k0)
+		if k.Cmp(big.NewInt(maxFractionDenominator)) > 0 {
+			break
+		}
+		best = new(big.Rat).SetFrac(h, k)
+		diff := new(big.Rat).Sub(best, x)
+		if diff.Abs(diff).Cmp(fractionTolerance) <= 0 {
+			break
+		}
+		r.Sub(r, new(big.Rat).SetInt(a))
+		if r.Sign() == 0 {
+			break
+		}
+		r.Inv(r)
+		h0, h1 = h1, h
+		k0, k1 = k1, k
+	}
+	return best
+}
+
+// Probability returns implied probability of the price, that is the inverse of
+// decimal odds. Fractional or HK decimal price is used without rounding. Error
+// is returned if price is empty.
+func (p *Price) Probability() (float64, error) {
+	var odds *big.Rat
+	switch {
+	case !p.Decimal.IsZero():
+		odds = p.Decimal.Rat()
+	case p.Fractional.Sign() > 0:
+		odds = new(big.Rat).Set(&p.Fractional)
+	default:
+		return 0, fmt.Errorf("price is empty")
+	}
+	odds.Add(odds, big.NewRat(1, 1))
+	prob, _ := odds.Inv(odds).Float64()
+	return prob, nil
+}
+
+// Validate checks that price fraction is well formed, that is it has a
+// positive denominator and a non negative numerator. Zero denominator received
+// from the feed is reported, although such price is parsed as an empty
+// fraction.
+func (p *Price) Validate() error {
+	if p.zeroDenominator {
+		return fmt.Errorf("price fraction has zero denominator")
+	}
+	if p.Fractional.Sign() < 0 {
+		return fmt.Errorf("price fraction %s is negative", p.Fractional.String())
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package greyhounds
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/advbet/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceDecimalOdds(t *testing.T) {
+	tests := []struct {
+		price Price
+		odds  decimal.Number
+		err   bool
+	}{
+		{
+			price: Price{Fractional: *big.NewRat(6, 1)},
+			odds:  makeDecimal(t, "7.00"),
+		},
+		{
+			// evens
+			price: Price{Fractional: *big.NewRat(1, 1)},
+			odds:  makeDecimal(t, "2.00"),
+		},
+		{
+			price: Price{Fractional: *big.NewRat(8, 13)},
+			odds:  makeDecimal(t, "1.62"),
+		},
+		{
+			// HK decimal price takes precedence
+			price: Price{Decimal: makeDecimal(t, "7.300"), Fractional: *big.NewRat(15, 2)},
+			odds:  makeDecimal(t, "8.300"),
+		},
+		{
+			price: Price{Decimal: makeDecimal(t, "1.600")},
+			odds:  makeDecimal(t, "2.600"),
+		},
+		{
+			price: Price{},
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		odds, err := test.price.DecimalOdds()
+		if test.err {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, test.odds, odds, test.price.FractionalString())
+	}
+}
+
+func TestPriceFractionalString(t *testing.T) {
+	tests := []struct {
+		price    Price
+		expected string
+	}{
+		{
+			price:    Price{Fractional: *big.NewRat(6, 1)},
+			expected: "6/1",
+		},
+		{
+			price:    Price{Fractional: *big.NewRat(1, 1)},
+			expected: "1/1",
+		},
+		{
+			price:    Price{Decimal: makeDecimal(t, "1.600")},
+			expected: "8/5",
+		},
+		{
+			price:    Price{Decimal: makeDecimal(t, "3.333")},
+			expected: "10/3",
+		},
+		{
+			price:    Price{},
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.price.FractionalString())
+	}
+}
+
+func TestPriceProbability(t *testing.T) {
+	tests := []struct {
+		price       Price
+		probability float64
+		err         bool
+	}{
+		{
+			price:       Price{Fractional: *big.NewRat(1, 1)},
+			probability: 0.5,
+		},
+		{
+			price:       Price{Fractional: *big.NewRat(5, 2)},
+			probability: 2.0 / 7,
+		},
+		{
+			price:       Price{Fractional: *big.NewRat(1, 3)},
+			probability: 0.75,
+		},
+		{
+			price:       Price{Decimal: makeDecimal(t, "1.500")},
+			probability: 0.4,
+		},
+		{
+			price: Price{},
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		prob, err := test.price.Probability()
+		if test.err {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.InDelta(t, test.probability, prob, 1e-9, test.price.FractionalString())
+	}
+}
+
+func TestPriceValidate(t *testing.T) {
+	tests := []struct {
+		price Price
+		err   bool
+	}{
+		{price: Price{}},
+		{price: Price{Fractional: *big.NewRat(6, 1)}},
+		{price: Price{Decimal: makeDecimal(t, "1.500")}},
+		{price: Price{Fractional: *big.NewRat(-6, 1)}, err: true},
+		{price: Price{Fractional: *big.NewRat(6, -1)}, err: true},
+		{price: Price{zeroDenominator: true}, err: true},
+	}
+
+	for _, test := range tests {
+		err := test.price.Validate()
+		if test.err {
+			assert.Error(t, err, test.price.FractionalString())
+		} else {
+			assert.NoError(t, err, test.price.FractionalString())
+		}
+	}
+}
+
+func TestPriceValidateZeroDenominator(t *testing.T) {
+	dr := parseTestFile(t, "testdata/Invalid/b2018041433736119270007.xml")
+	require.NotEmpty(t, dr.Meetings)
+	require.NotEmpty(t, dr.Meetings[0].Races)
+	traps := dr.Meetings[0].Races[0].Traps
+	require.NotEmpty(t, traps[0].Shows)
+	require.NotEmpty(t, traps[1].Shows)
+
+	invalid := traps[0].Shows[0].Price
+	require.NotNil(t, invalid)
+	assert.Equal(t, 0, invalid.Fractional.Sign())
+	assert.Error(t, invalid.Validate())
+
+	valid := traps[1].Shows[0].Price
+	require.NotNil(t, valid)
+	assert.NoError(t, valid.Validate())
+}
+
+func TestPriceFraction(t *testing.T) {
+	tests := []struct {
+		price    Price
+		expected string
+	}{
+		{price: Price{}, expected: ""},
+		{price: Price{Fractional: *big.NewRat(6, 4)}, expected: "3/2"},
+		{price: Price{Decimal: makeDecimal(t, "8.000")}, expected: "8/1"},
+		{price: Price{Decimal: makeDecimal(t, "3.333")}, expected: "10/3"},
+		{price: Price{Decimal: makeDecimal(t, "0.909")}, expected: "10/11"},
+		{price: Price{Decimal: makeDecimal(t, "6.100")}, expected: "61/10"},
+		{price: Price{Decimal: makeDecimal(t, "0.010")}, expected: "1/100"},
+	}
+
+	for _, test := range tests {
+		fraction := test.price.Fraction()
+		if test.expected == "" {
+			assert.Nil(t, fraction)
+			continue
+		}
+		require.NotNil(t, fraction, test.expected)
+		assert.Equal(t, test.expected, fraction.String())
+	}
+}
+
+func TestParseDecimalOnlyPrices(t *testing.T) {
+	// synthetic copy of the same race with prices in HK decimal format only
+	fractional := testRace(t, "testdata/The Meadows/b201804143181110017.xml")
+	decimalOnly := testRace(t, "testdata/Synthetic/decimal-only-prices.xml")
+
+	require.Len(t, decimalOnly.Traps, len(fractional.Traps))
+	for i, trap := range decimalOnly.Traps {
+		require.Len(t, trap.Shows, len(fractional.Traps[i].Shows))
+		for j, s := range trap.Shows {
+			expected := fractional.Traps[i].Shows[j].Price
+			if expected == nil {
+				assert.Nil(t, s.Price)
+				continue
+			}
+			require.NotNil(t, s.Price)
+			assert.Zero(t, s.Price.Fractional.Sign())
+			assert.Equal(t, 0, expected.Fractional.Cmp(s.Price.Fraction()), "trap %d show %d", trap.TrapNo, j)
+			assert.Equal(t, expected.FractionalString(), s.Price.FractionalString(), "trap %d show %d", trap.TrapNo, j)
+
+			prob, err := s.Price.Probability()
+			require.NoError(t, err)
+			expectedProb, _ := expected.Probability()
+			assert.InDelta(t, expectedProb, prob, 0.001)
+		}
+	}
+}
@@ -0,0 +1,644 @@
+package greyhounds
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/advbet/decimal"
+)
+
+// IsSeeded returns true if the trap has a seeding assigned. Tracks that do not
+// seed their races leave seeding empty.
+func (t *Trap) IsSeeded() bool {
+	return t.Seeding != SeedingNone
+}
+
+// RaceByNumber returns race of the meeting having the given race number.
+func (m *Meeting) RaceByNumber(n int) (*Race, bool) {
+	for i := range m.Races {
+		if m.Races[i].RaceNumber == n {
+			return &m.Races[i], true
+		}
+	}
+	return nil, false
+}
+
+// TrapByNumber returns trap of the race having the given trap number.
+func (r *Race) TrapByNumber(n int) (*Trap, bool) {
+	for i := range r.Traps {
+		if r.Traps[i].TrapNo == n {
+			return &r.Traps[i], true
+		}
+	}
+	return nil, false
+}
+
+// DogByID returns dog running in the race having the given ID.
+func (r *Race) DogByID(id int) (*Dog, bool) {
+	for i := range r.Traps {
+		if d := r.Traps[i].Dog; d != nil && d.ID == id {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// WinnerWasFavourite returns true if the race winner was the (joint) starting
+// price favourite. False is returned if the race has no winner yet.
+func (r *Race) WinnerWasFavourite() bool {
+	for _, t := range r.Traps {
+		if t.isWinner() && t.Result.MarketPosition == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// hasWinner returns true if at least one trap in the race has won it.
+func (r *Race) hasWinner() bool {
+	for _, t := range r.Traps {
+		if t.isWinner() {
+			return true
+		}
+	}
+	return false
+}
+
+// isWinner returns true if dog from this trap won the race.
+func (t *Trap) isWinner() bool {
+	if t.Result == nil {
+		return false
+	}
+	place, _ := ParseResult(t.Result.Position)
+	return place == 1
+}
+
+// Winner returns the trap of the race winner. The first trap is returned if
+// dogs dead-heated for the win, use Winners to get all of them. False is
+// returned if the race has no winner yet.
+func (r *Race) Winner() (*Trap, bool) {
+	for i := range r.Traps {
+		if r.Traps[i].isWinner() {
+			return &r.Traps[i], true
+		}
+	}
+	return nil, false
+}
+
+// Winners returns traps of all the race winners, more than one trap is returned
+// if dogs dead-heated for the win. Nil is returned if the race has no winner
+// yet.
+func (r *Race) Winners() []*Trap {
+	var winners []*Trap
+	for i := range r.Traps {
+		if r.Traps[i].isWinner() {
+			winners = append(winners, &r.Traps[i])
+		}
+	}
+	return winners
+}
+
+// DeadHeats returns groups of traps of dogs sharing a finishing position, in
+// order of the position. The first group holds all the co-winners if dogs
+// dead-heated for the win. Nil is returned if there were no dead heats.
+func (r *Race) DeadHeats() [][]*Trap {
+	var groups [][]*Trap
+	var group []*Trap
+	place := 0
+	for _, t := range r.FinishingOrder() {
+		p, _ := ParseResult(t.Result.Position)
+		if len(group) > 0 && p != place {
+			if len(group) > 1 {
+				groups = append(groups, group)
+			}
+			group = nil
+		}
+		group = append(group, t)
+		place = p
+	}
+	if len(group) > 1 {
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// FinishingOrder returns traps of placed dogs sorted by finishing position.
+// Dogs that did not finish or were not placed are omitted. Dead-heated dogs
+// share the position and are returned in trap order.
+func (r *Race) FinishingOrder() []*Trap {
+	var traps []*Trap
+	for i := range r.Traps {
+		t := &r.Traps[i]
+		if t.Result == nil {
+			continue
+		}
+		if place, _ := ParseResult(t.Result.Position); place > 0 {
+			traps = append(traps, t)
+		}
+	}
+	sort.SliceStable(traps, func(i, j int) bool {
+		pi, _ := ParseResult(traps[i].Result.Position)
+		pj, _ := ParseResult(traps[j].Result.Position)
+		return pi < pj
+	})
+	return traps
+}
+
+// BeatenFavourites returns a list of races having a result where the starting
+// price favourite did not win.
+func (r *DogRacing) BeatenFavourites() []*Race {
+	var races []*Race
+	for i := range r.Meetings {
+		for j := range r.Meetings[i].Races {
+			race := &r.Meetings[i].Races[j]
+			if race.hasWinner() && !race.WinnerWasFavourite() {
+				races = append(races, race)
+			}
+		}
+	}
+	return races
+}
+
+// FavouriteExcludingNonRunners returns the trap with the shortest priced dog
+// among dogs still running in the race. Vacant traps and traps listed as non
+// runners are ignored. Starting price is used if known, otherwise the latest
+// offered show of the first betting market. The first of the joint favourites
+// is returned. Nil is returned if none of the runners is priced.
+func (r *Race) FavouriteExcludingNonRunners() *Trap {
+	nonRunners := make(map[int]bool, len(r.NonRunners))
+	for _, nr := range r.NonRunners {
+		nonRunners[nr.Trap] = true
+	}
+
+	var fav *Trap
+	var favProb float64
+	for i := range r.Traps {
+		t := &r.Traps[i]
+		if t.Vacant || t.Dog == nil || nonRunners[t.TrapNo] {
+			continue
+		}
+		p := t.currentPrice()
+		if p == nil {
+			continue
+		}
+		prob, err := p.Probability()
+		if err != nil {
+			continue
+		}
+		if fav == nil || prob > favProb {
+			fav, favProb = t, prob
+		}
+	}
+	return fav
+}
+
+// IsLocalTo returns true if trainer is local to the given track. Track names
+// are compared case-insensitively.
+func (t *Trainer) IsLocalTo(track string) bool {
+	return t.Track != "" && strings.EqualFold(t.Track, track)
+}
+
+// LocalTrainerDogs returns a list of traps having dogs trained by a trainer
+// local to the given track.
+func (r *Race) LocalTrainerDogs(track string) []*Trap {
+	var traps []*Trap
+	for i := range r.Traps {
+		t := &r.Traps[i]
+		if t.Dog != nil && t.Dog.Trainer.IsLocalTo(track) {
+			traps = append(traps, t)
+		}
+	}
+	return traps
+}
+
+// colourCodes maps greyhound colour abbreviations to colour names.
+var colourCodes = map[string]string{
+	"bk": "black",
+	"bd": "brindle",
+	"be": "blue",
+	"dk": "dark",
+	"lt": "light",
+	"f":  "fawn",
+	"r":  "red",
+	"w":  "white",
+	"t":  "ticked",
+}
+
+// ColourName returns the colour of the dog spelled out in full, e.g. "bd" is
+// returned as "brindle" and "wbk" as "white black". Colours that are not coded
+// are returned as is. Returned name is always in lower case.
+func (b *Breeding) ColourName() string {
+	colour := strings.ToLower(strings.TrimSpace(b.Colour))
+	var names []string
+	for rest := colour; rest != ""; {
+		var name string
+		if len(rest) >= 2 {
+			name = colourCodes[rest[:2]]
+		}
+		if name != "" {
+			rest = rest[2:]
+		} else if name = colourCodes[rest[:1]]; name != "" {
+			rest = rest[1:]
+		} else {
+			return colour
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, " ")
+}
+
+// Seconds returns the going allowance in seconds, e.g. "-10" is returned as
+// -0.1 and normal going "N" as zero. False is returned if the allowance is
+// empty or not numeric, the raw value is left for the caller to interpret.
+func (g GoingAllowance) Seconds() (float64, bool) {
+	s := strings.TrimSpace(string(g))
+	if strings.EqualFold(s, "N") {
+		return 0, true
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, false
+	}
+	return float64(n) / 100, true
+}
+
+// GoingAllowance returns the going allowance of the race in seconds. False is
+// returned if the allowance is not known or not numeric.
+func (r *Race) GoingAllowance() (float64, bool) {
+	return GoingAllowance(r.Going).Seconds()
+}
+
+// GoingAllowance returns the going allowance of the form race in seconds. False
+// is returned if the allowance is not known or not numeric.
+func (r *FormRace) GoingAllowance() (float64, bool) {
+	return GoingAllowance(r.Going).Seconds()
+}
+
+// ParseGrade parses race class to a structured grade. Class made of a single
+// letter optionally followed by a number, e.g. "A7", "D" or "S1", is a grade.
+// Other classes, e.g. open race "OR", hurdle "HP" or trial "T2", are returned
+// with the raw value only.
+func ParseGrade(class string) GreyhoundGrade {
+	grade := GreyhoundGrade{Raw: class}
+	s := strings.TrimSpace(class)
+	if s == "" || s[0] < 'A' || s[0] > 'Z' {
+		return grade
+	}
+	if len(s) == 1 {
+		grade.Letter = s
+		return grade
+	}
+	if s[0] == 'T' { // trials T1-T4 are not graded races
+		return grade
+	}
+	n, err := strconv.Atoi(s[1:])
+	if err != nil || n <= 0 || s[1] < '0' || s[1] > '9' {
+		return grade
+	}
+	grade.Letter = s[:1]
+	grade.Number = n
+	return grade
+}
+
+// Grade returns the race class parsed to a structured grade, see ParseGrade.
+func (r *Race) Grade() GreyhoundGrade {
+	return ParseGrade(r.Class)
+}
+
+// Grade returns the form race class parsed to a structured grade, see
+// ParseGrade.
+func (r *FormRace) Grade() GreyhoundGrade {
+	return ParseGrade(r.Class)
+}
+
+// Duration returns the expected time value parsed in the same mmss.ss format
+// as other feed durations, e.g. "28.45" is 28.45 seconds. Error is returned if
+// value is empty or malformed.
+func (t ExpectedTime) Duration() (time.Duration, error) {
+	if t.Value == "" {
+		return 0, fmt.Errorf("expected time value is empty")
+	}
+	return parseDuration(t.Value)
+}
+
+// ExpectedFinalTime returns the dog expected final time. The first expected
+// time of "final" type having a valid value is used. False is returned if dog
+// has no such time.
+func (d *Dog) ExpectedFinalTime() (time.Duration, bool) {
+	for _, t := range d.ExpectedTimes {
+		if !strings.EqualFold(t.Type, "final") {
+			continue
+		}
+		if duration, err := t.Duration(); err == nil && duration > 0 {
+			return duration, true
+		}
+	}
+	return 0, false
+}
+
+// FormSummary holds statistics of the dog form races.
+type FormSummary struct {
+	Runs   int // Number of form races the dog ran in
+	Wins   int // Number of form races won
+	Places int // Number of form races finished second or third
+
+	DistanceRuns  int           // Number of form races run at the given distance
+	DistanceWins  int           // Number of form races won at the given distance
+	WinPercentage float64       // Percentage of form races won at the given distance
+	BestTime      time.Duration // The fastest time at the given distance, zero if not known
+}
+
+// FormSummary returns statistics of the dog form races. Runs, wins and places
+// are counted over all form races, positions are parsed using ParseResult.
+// Win percentage and the best time are computed over form races run at the
+// given distance, usually the distance of the race the dog is entered for.
+// Adjusted time is used for the best time, run time if adjusted time is not
+// known.
+func (d *Dog) FormSummary(distance int) FormSummary {
+	var summary FormSummary
+	for i := range d.FormRaces {
+		r := &d.FormRaces[i]
+		t, ok := r.trapOf(d.ID)
+		if !ok || t.Result == nil {
+			continue
+		}
+		position, dnf := ParseResult(t.Result.Position)
+		summary.Runs++
+		switch position {
+		case 1:
+			summary.Wins++
+		case 2, 3:
+			summary.Places++
+		}
+		if r.Distance != distance {
+			continue
+		}
+		summary.DistanceRuns++
+		if position == 1 {
+			summary.DistanceWins++
+		}
+		runTime := t.Result.AdjustedTime
+		if runTime == 0 {
+			runTime = t.Result.RunTime
+		}
+		if !dnf && runTime > 0 && (summary.BestTime == 0 || runTime < summary.BestTime) {
+			summary.BestTime = runTime
+		}
+	}
+	if summary.DistanceRuns > 0 {
+		summary.WinPercentage = float64(summary.DistanceWins) / float64(summary.DistanceRuns) * 100
+	}
+	return summary
+}
+
+// trapOf returns form trap of the dog having the given ID.
+func (r *FormRace) trapOf(dogID int) (*FormTrap, bool) {
+	for i := range r.FormTraps {
+		if d := r.FormTraps[i].Dog; d != nil && d.ID == dogID {
+			return &r.FormTraps[i], true
+		}
+	}
+	return nil, false
+}
+
+// FastestExpected returns traps of dogs having the fastest expected final time
+// in the race, several traps are returned if dogs share the time. Vacant traps
+// and dogs without expected final time are skipped.
+func (r *Race) FastestExpected() []*Trap {
+	var fastest []*Trap
+	var best time.Duration
+	for i := range r.Traps {
+		t := &r.Traps[i]
+		if t.Vacant || t.Dog == nil {
+			continue
+		}
+		duration, ok := t.Dog.ExpectedFinalTime()
+		switch {
+		case !ok:
+		case len(fastest) == 0 || duration < best:
+			fastest = []*Trap{t}
+			best = duration
+		case duration == best:
+			fastest = append(fastest, t)
+		}
+	}
+	return fastest
+}
+
+// ExpectedTimeBehind returns how much the expected final time of the dog in
+// the given trap is slower than the fastest expected final time in the race,
+// zero for the fastest dogs. False is returned if the trap has no dog with an
+// expected final time.
+func (r *Race) ExpectedTimeBehind(trapNo int) (time.Duration, bool) {
+	t, ok := r.TrapByNumber(trapNo)
+	if !ok || t.Vacant || t.Dog == nil {
+		return 0, false
+	}
+	duration, ok := t.Dog.ExpectedFinalTime()
+	if !ok {
+		return 0, false
+	}
+	fastest := r.FastestExpected()
+	best, _ := fastest[0].Dog.ExpectedFinalTime()
+	return duration - best, true
+}
+
+// TimeWindow returns the earliest and the latest scheduled race start times in
+// the document. Zero times are returned if document has no races.
+func (r *DogRacing) TimeWindow() (first, last time.Time) {
+	for _, m := range r.Meetings {
+		for _, race := range m.Races {
+			if first.IsZero() || race.Time.Before(first) {
+				first = race.Time
+			}
+			if last.IsZero() || race.Time.After(last) {
+				last = race.Time
+			}
+		}
+	}
+	return first, last
+}
+
+// ForecastDividend returns the forecast dividend declared for the given 1st and
+// 2nd placed traps. False is returned if no such dividend is declared.
+func (d *Dividends) ForecastDividend(trap1, trap2 int) (decimal.Number, bool) {
+	for _, f := range d.Forecast {
+		if f.Trap1 == trap1 && f.Trap2 == trap2 {
+			return f.Dividend, true
+		}
+	}
+	return decimal.Number{}, false
+}
+
+// TricastDividend returns the tricast dividend declared for the given 1st, 2nd
+// and 3rd placed traps. False is returned if no such dividend is declared.
+func (d *Dividends) TricastDividend(trap1, trap2, trap3 int) (decimal.Number, bool) {
+	for _, t := range d.Tricast {
+		if t.Trap1 == trap1 && t.Trap2 == trap2 && t.Trap3 == trap3 {
+			return t.Dividend, true
+		}
+	}
+	return decimal.Number{}, false
+}
+
+// WinningForecast returns the forecast dividend of the race together with the
+// 1st and 2nd placed trap numbers taken from the finishing order. False is
+// returned if the race has no result or dividends yet, or if dead heats leave
+// the 1st and 2nd places ambiguous.
+func (r *Race) WinningForecast() (decimal.Number, []int, bool) {
+	traps, ok := r.placedTraps(2)
+	if !ok || r.Dividends == nil {
+		return decimal.Number{}, nil, false
+	}
+	dividend, ok := r.Dividends.ForecastDividend(traps[0], traps[1])
+	if !ok {
+		return decimal.Number{}, nil, false
+	}
+	return dividend, traps, true
+}
+
+// WinningTricast returns the tricast dividend of the race together with the
+// 1st, 2nd and 3rd placed trap numbers taken from the finishing order. False is
+// returned if the race has no result or dividends yet, or if dead heats leave
+// the places ambiguous.
+func (r *Race) WinningTricast() (decimal.Number, []int, bool) {
+	traps, ok := r.placedTraps(3)
+	if !ok || r.Dividends == nil {
+		return decimal.Number{}, nil, false
+	}
+	dividend, ok := r.Dividends.TricastDividend(traps[0], traps[1], traps[2])
+	if !ok {
+		return decimal.Number{}, nil, false
+	}
+	return dividend, traps, true
+}
+
+// placedTraps returns trap numbers of the first n places. False is returned if
+// any of the places is missing or shared by dead-heated dogs.
+func (r *Race) placedTraps(n int) ([]int, bool) {
+	order := r.FinishingOrder()
+	if len(order) < n {
+		return nil, false
+	}
+	traps := make([]int, n)
+	for i := range traps {
+		if place, _ := ParseResult(order[i].Result.Position); place != i+1 {
+			return nil, false
+		}
+		traps[i] = order[i].TrapNo
+	}
+	if len(order) > n {
+		if place, _ := ParseResult(order[n].Result.Position); place == n {
+			return nil, false
+		}
+	}
+	return traps, true
+}
+
+// Market returns the number of betting market the show is applicable to. Show
+// without market number, or with market number zero, belongs to the first
+// (only) market.
+func (s Show) Market() int {
+	if s.MarketNumber == nil || *s.MarketNumber == 0 {
+		return 1
+	}
+	return *s.MarketNumber
+}
+
+// LatestShow returns the most recent show by timestamp in the given betting
+// market. Market number zero is treated as the first market. Shows having no
+// offers are skipped. If several shows have the same timestamp the last one in
+// feed order is returned.
+func (t *Trap) LatestShow(marketNumber int) (*Show, bool) {
+	if marketNumber == 0 {
+		marketNumber = 1
+	}
+	s := t.latestShow(marketNumber, true)
+	return s, s != nil
+}
+
+// CurrentPrice returns price of the latest offered show in the given betting
+// market, see LatestShow for details.
+func (t *Trap) CurrentPrice(marketNumber int) (*Price, bool) {
+	s, ok := t.LatestShow(marketNumber)
+	if !ok {
+		return nil, false
+	}
+	return s.Price, true
+}
+
+// latestShow returns the most recent show by timestamp in the given betting
+// market, nil is returned if there are no shows. Shows without offers are
+// skipped if offered is true.
+func (t *Trap) latestShow(marketNumber int, offered bool) *Show {
+	var latest *Show
+	for i, s := range t.Shows {
+		if s.Market() != marketNumber {
+			continue
+		}
+		if offered && (s.NoOffers || s.Price == nil) {
+			continue
+		}
+		if latest == nil || !s.TimeStamp.Before(latest.TimeStamp) {
+			latest = &t.Shows[i]
+		}
+	}
+	return latest
+}
+
+// Overround returns the sum of implied probabilities of the latest shows of
+// all traps in the given betting market. Value above 1 is the bookmaker margin,
+// e.g. 1.15 is 15% overround. Shows without market number belong to the first
+// market. Vacant traps and traps having no offers are skipped. Error is
+// returned if no trap has a price in the market.
+func (r *Race) Overround(marketNumber int) (float64, error) {
+	if marketNumber == 0 {
+		marketNumber = 1
+	}
+	var sum float64
+	priced := 0
+	for _, t := range r.Traps {
+		if t.Vacant {
+			continue
+		}
+		latest := t.latestShow(marketNumber, false)
+		if latest == nil || latest.NoOffers || latest.Price == nil {
+			continue
+		}
+		prob, err := latest.Price.Probability()
+		if err != nil {
+			return 0, fmt.Errorf("trap %d: %v", t.TrapNo, err)
+		}
+		sum += prob
+		priced++
+	}
+	if priced == 0 {
+		return 0, fmt.Errorf("no prices in market %d", marketNumber)
+	}
+	return sum, nil
+}
+
+// Bends returns the dog's position at each bend parsed from BendPosition. The
+// feed writes a single digit per bend, e.g. "3221", comma separated positions
+// (e.g. "3,2,2,1") are accepted as well. Position of a missed section ("-")
+// is returned as zero.
+func (r *Result) Bends() []int {
+	if r.BendPosition == "" {
+		return nil
+	}
+	var parts []string
+	if strings.Contains(r.BendPosition, ",") {
+		parts = strings.Split(r.BendPosition, ",")
+	} else {
+		parts = strings.Split(r.BendPosition, "")
+	}
+	bends := make([]int, 0, len(parts))
+	for _, p := range parts {
+		pos, _ := strconv.Atoi(strings.TrimSpace(p))
+		bends = append(bends, pos)
+	}
+	return bends
+}
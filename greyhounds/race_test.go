@@ -0,0 +1,707 @@
+package greyhounds
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseTestFile(t *testing.T, file string) *DogRacing {
+	blob, err := ioutil.ReadFile(file)
+	require.NoError(t, err, file)
+	obj, err := ParseFile(blob)
+	require.NoError(t, err, file)
+	return obj
+}
+
+func TestTrapIsSeeded(t *testing.T) {
+	tests := []struct {
+		file     string
+		seedings map[int]TrapSeeding
+	}{
+		{
+			file: "testdata/Crayford/b2018041433736119270007.xml",
+			seedings: map[int]TrapSeeding{
+				1: SeedingNone,
+				2: SeedingNone,
+				3: SeedingNone,
+				4: SeedingNone,
+				5: SeedingNone,
+				6: SeedingNone,
+			},
+		},
+		{
+			file: "testdata/Crayford/b201804143373612017.xml",
+			seedings: map[int]TrapSeeding{
+				1: SeedingNone,
+				2: SeedingNone,
+				3: SeedingNone,
+				4: SeedingWide,
+				5: SeedingWide,
+				6: SeedingWide,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		obj := parseTestFile(t, test.file)
+		for _, trap := range obj.Meetings[0].Races[0].Traps {
+			seeding, ok := test.seedings[trap.TrapNo]
+			require.True(t, ok, test.file)
+			assert.Equal(t, seeding, trap.Seeding, test.file)
+			assert.Equal(t, seeding != SeedingNone, trap.IsSeeded(), test.file)
+		}
+	}
+}
+
+func TestParseTrapUnknownSeeding(t *testing.T) {
+	var trap xmlTrap
+	require.NoError(t, xml.Unmarshal([]byte(`<Trap trap="2" seeding="Centre"/>`), &trap))
+	parsed := Trap(trap)
+	assert.Equal(t, TrapSeeding("Centre"), parsed.Seeding)
+	assert.True(t, parsed.IsSeeded())
+}
+
+func TestBeatenFavourites(t *testing.T) {
+	tests := []struct {
+		file       string
+		favourites []int
+		beaten     []int
+		favWins    bool
+	}{
+		{
+			// joint favourites in traps 2, 3 and 4 beaten by trap 1
+			file:       "testdata/Crayford/b201804143373611927.xml",
+			favourites: []int{2, 3, 4},
+			beaten:     []int{1},
+			favWins:    false,
+		},
+		{
+			file:       "testdata/Crayford/b201804143373611943.xml",
+			favourites: []int{1, 4, 5},
+			beaten:     nil,
+			favWins:    true,
+		},
+		{
+			// race not yet run
+			file:       "testdata/Crayford/b2018041433736119270007.xml",
+			favourites: nil,
+			beaten:     nil,
+			favWins:    false,
+		},
+	}
+
+	for _, test := range tests {
+		obj := parseTestFile(t, test.file)
+		var favourites []int
+		for _, trap := range obj.Meetings[0].Races[0].Traps {
+			if trap.Result != nil && trap.Result.MarketPosition == 1 {
+				favourites = append(favourites, trap.TrapNo)
+			}
+		}
+		assert.Equal(t, test.favourites, favourites, test.file)
+		var beaten []int
+		for _, r := range obj.BeatenFavourites() {
+			beaten = append(beaten, r.RaceNumber)
+		}
+		assert.Equal(t, test.beaten, beaten, test.file)
+		assert.Equal(t, test.favWins, obj.Meetings[0].Races[0].WinnerWasFavourite(), test.file)
+	}
+}
+
+func TestRaceOffTime(t *testing.T) {
+	tests := []struct {
+		file    string
+		offTime *time.Time
+	}{
+		{
+			// pre-race message, race is not off yet
+			file:    "testdata/Crayford/b2018041433736119270007.xml",
+			offTime: nil,
+		},
+		{
+			file:    "testdata/Crayford/b201804143373611927.xml",
+			offTime: timePtr(makeTime(t, "2018-04-14T19:27:54+01:00")),
+		},
+	}
+
+	for _, test := range tests {
+		obj := parseTestFile(t, test.file)
+		offTime := obj.Meetings[0].Races[0].OffTime
+		if test.offTime == nil {
+			assert.Nil(t, offTime, test.file)
+			continue
+		}
+		require.NotNil(t, offTime, test.file)
+		assert.True(t, test.offTime.Equal(*offTime), test.file)
+	}
+}
+
+func TestRaceFavouriteExcludingNonRunners(t *testing.T) {
+	file := "testdata/The Meadows/b201804143181110017.xml"
+	race := testRace(t, file)
+	fav := race.FavouriteExcludingNonRunners()
+	require.NotNil(t, fav)
+	assert.Equal(t, 6, fav.TrapNo)
+
+	// non runner in trap 7 shortened to be the market leader
+	race = testRace(t, file)
+	require.Equal(t, 7, race.NonRunners[0].Trap)
+	last := race.Traps[6].Shows[len(race.Traps[6].Shows)-1]
+	race.Traps[6].Shows = append(race.Traps[6].Shows, Show{
+		TimeStamp: last.TimeStamp.Add(time.Minute),
+		Price:     &Price{Fractional: *big.NewRat(1, 2)},
+	})
+	fav = race.FavouriteExcludingNonRunners()
+	require.NotNil(t, fav)
+	assert.Equal(t, 6, fav.TrapNo)
+
+	race.NonRunners = nil
+	fav = race.FavouriteExcludingNonRunners()
+	require.NotNil(t, fav)
+	assert.Equal(t, 7, fav.TrapNo)
+
+	assert.Nil(t, (&Race{}).FavouriteExcludingNonRunners())
+}
+
+func TestRaceLocalTrainerDogs(t *testing.T) {
+	tests := []struct {
+		file  string
+		track string
+		traps []int
+	}{
+		{
+			file:  "testdata/Crayford/c20180414cra5_337361.xml",
+			track: "Crayford",
+			traps: []int{1, 2, 3, 4, 5, 6},
+		},
+		{
+			file:  "testdata/Crayford/c20180414cra5_337361.xml",
+			track: "CRAYFORD",
+			traps: []int{1, 2, 3, 4, 5, 6},
+		},
+		{
+			file:  "testdata/Crayford/c20180414cra5_337361.xml",
+			track: "Nottingham",
+			traps: nil,
+		},
+		{
+			// Trainer track is empty in Australian feed
+			file:  "testdata/The Meadows/c20180414atm_3181.xml",
+			track: "The Meadows",
+			traps: nil,
+		},
+	}
+
+	for _, test := range tests {
+		obj := parseTestFile(t, test.file)
+		var traps []int
+		for _, trap := range obj.Meetings[0].Races[0].LocalTrainerDogs(test.track) {
+			traps = append(traps, trap.TrapNo)
+		}
+		assert.Equal(t, test.traps, traps, test.track)
+	}
+}
+
+func TestBreedingColourName(t *testing.T) {
+	tests := []struct {
+		colour string
+		name   string
+	}{
+		{colour: "bk", name: "black"},
+		{colour: "BK", name: "black"},
+		{colour: "bd", name: "brindle"},
+		{colour: "f", name: "fawn"},
+		{colour: "be", name: "blue"},
+		{colour: "wbk", name: "white black"},
+		{colour: "bkw", name: "black white"},
+		{colour: "RBD", name: "red brindle"},
+		{colour: "dkbd", name: "dark brindle"},
+		{colour: "LTF", name: "light fawn"},
+		{colour: "bebd", name: "blue brindle"},
+		{colour: "Black", name: "black"},
+		{colour: "Red Brindle", name: "red brindle"},
+		{colour: "", name: ""},
+	}
+
+	for _, test := range tests {
+		b := Breeding{Colour: test.colour}
+		assert.Equal(t, test.name, b.ColourName(), test.colour)
+	}
+}
+
+func TestGoingAllowanceSeconds(t *testing.T) {
+	tests := []struct {
+		going   GoingAllowance
+		seconds float64
+		ok      bool
+	}{
+		{going: "0", seconds: 0, ok: true},
+		{going: "10", seconds: 0.1, ok: true},
+		{going: "+15", seconds: 0.15, ok: true},
+		{going: "-30", seconds: -0.3, ok: true},
+		{going: "110", seconds: 1.1, ok: true},
+		{going: "N", seconds: 0, ok: true},
+		{going: "n", seconds: 0, ok: true},
+		{going: "", ok: false},
+		{going: "Slow", ok: false},
+	}
+
+	for _, test := range tests {
+		seconds, ok := test.going.Seconds()
+		assert.Equal(t, test.ok, ok, test.going)
+		assert.InDelta(t, test.seconds, seconds, 1e-9, test.going)
+	}
+}
+
+func TestParseGrade(t *testing.T) {
+	tests := []struct {
+		class    string
+		expected GreyhoundGrade
+	}{
+		{class: "A7", expected: GreyhoundGrade{Raw: "A7", Letter: "A", Number: 7}},
+		{class: "S1", expected: GreyhoundGrade{Raw: "S1", Letter: "S", Number: 1}},
+		{class: "A10", expected: GreyhoundGrade{Raw: "A10", Letter: "A", Number: 10}},
+		{class: "D", expected: GreyhoundGrade{Raw: "D", Letter: "D"}},
+		{class: "OR", expected: GreyhoundGrade{Raw: "OR"}},
+		{class: "HP", expected: GreyhoundGrade{Raw: "HP"}},
+		{class: "T1", expected: GreyhoundGrade{Raw: "T1"}},
+		{class: "T4", expected: GreyhoundGrade{Raw: "T4"}},
+		{class: "A-1", expected: GreyhoundGrade{Raw: "A-1"}},
+		{class: "", expected: GreyhoundGrade{}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, ParseGrade(test.class), test.class)
+	}
+}
+
+func TestRaceGrade(t *testing.T) {
+	race := testRace(t, "testdata/Crayford/b2018041433736119270028.xml")
+	assert.Equal(t, GreyhoundGrade{Raw: "A7", Letter: "A", Number: 7}, race.Grade())
+
+	form := FormRace{Class: "OR"}
+	assert.Equal(t, GreyhoundGrade{Raw: "OR"}, form.Grade())
+}
+
+func TestRaceGoingAllowance(t *testing.T) {
+	race := testRace(t, "testdata/Nottingham/b201804143373662237.xml")
+	seconds, ok := race.GoingAllowance()
+	require.True(t, ok)
+	assert.InDelta(t, 0.3, seconds, 1e-9)
+
+	form := FormRace{Going: "-10"}
+	seconds, ok = form.GoingAllowance()
+	require.True(t, ok)
+	assert.InDelta(t, -0.1, seconds, 1e-9)
+
+	_, ok = (&Race{}).GoingAllowance()
+	assert.False(t, ok)
+}
+
+func TestDogRacingTimeWindow(t *testing.T) {
+	obj := parseTestFile(t, "testdata/Crayford/c20180414cra5_337361.xml")
+	first, last := obj.TimeWindow()
+	assert.WithinDuration(t, makeTime(t, "2018-04-14T19:27:00+01:00"), first, 0)
+	assert.WithinDuration(t, makeTime(t, "2018-04-14T22:35:00+01:00"), last, 0)
+
+	first, last = (&DogRacing{}).TimeWindow()
+	assert.True(t, first.IsZero())
+	assert.True(t, last.IsZero())
+}
+
+func TestRaceOverround(t *testing.T) {
+	tests := []struct {
+		file      string
+		market    int
+		overround float64
+		err       bool
+	}{
+		{
+			file:      "testdata/Crayford/b2018041433736119270007.xml",
+			market:    1,
+			overround: 115.0 / 84,
+		},
+		{
+			file:      "testdata/Crayford/b2018041433736119270028.xml",
+			market:    1,
+			overround: 11236.0 / 9009,
+		},
+		{
+			file:   "testdata/Crayford/b2018041433736119270028.xml",
+			market: 2,
+			err:    true,
+		},
+		{
+			// no shows before the market is formed
+			file:   "testdata/Crayford/b2018041433736119270001.xml",
+			market: 1,
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		obj := parseTestFile(t, test.file)
+		overround, err := obj.Meetings[0].Races[0].Overround(test.market)
+		if test.err {
+			assert.Error(t, err, test.file)
+			continue
+		}
+		assert.NoError(t, err, test.file)
+		assert.InDelta(t, test.overround, overround, 1e-9, test.file)
+	}
+}
+
+func TestResultBends(t *testing.T) {
+	tests := []struct {
+		position string
+		bends    []int
+	}{
+		{position: "3221", bends: []int{3, 2, 2, 1}},
+		{position: "1-1-", bends: []int{1, 0, 1, 0}},
+		{position: "1,2,3,3", bends: []int{1, 2, 3, 3}},
+		{position: "1,-,3", bends: []int{1, 0, 3}},
+		{position: "", bends: nil},
+	}
+
+	for _, test := range tests {
+		r := Result{BendPosition: test.position}
+		assert.Equal(t, test.bends, r.Bends(), test.position)
+	}
+}
+
+func TestRaceFinishingOrder(t *testing.T) {
+	// dead heat for the third place
+	race := testRace(t, "testdata/Crayford/b2018041433736122020031.xml")
+	w, ok := race.Winner()
+	require.True(t, ok)
+	assert.Equal(t, 4, w.TrapNo)
+
+	var order []int
+	for _, trap := range race.FinishingOrder() {
+		order = append(order, trap.TrapNo)
+	}
+	assert.Equal(t, []int{4, 1, 3, 6}, order)
+
+	// dead heat for the win, dog that did not finish
+	race = Race{
+		Traps: []Trap{
+			{TrapNo: 1, Result: &Result{Position: "DN"}},
+			{TrapNo: 2, Result: &Result{Position: "2"}},
+			{TrapNo: 3, Result: &Result{Position: "1"}},
+			{TrapNo: 4},
+			{TrapNo: 5, Result: &Result{Position: "1"}},
+		},
+	}
+	w, ok = race.Winner()
+	require.True(t, ok)
+	assert.Equal(t, 3, w.TrapNo)
+	order = nil
+	for _, trap := range race.FinishingOrder() {
+		order = append(order, trap.TrapNo)
+	}
+	assert.Equal(t, []int{3, 5, 2}, order)
+
+	_, ok = (&Race{}).Winner()
+	assert.False(t, ok)
+}
+
+func TestRaceDeadHeats(t *testing.T) {
+	// dead heat for the third place
+	race := testRace(t, "testdata/Crayford/b2018041433736122020031.xml")
+	var groups [][]int
+	for _, group := range race.DeadHeats() {
+		var traps []int
+		for _, trap := range group {
+			traps = append(traps, trap.TrapNo)
+		}
+		groups = append(groups, traps)
+	}
+	assert.Equal(t, [][]int{{3, 6}}, groups)
+	require.Len(t, race.Winners(), 1)
+	assert.Equal(t, 4, race.Winners()[0].TrapNo)
+
+	// dead heat for the win
+	race = Race{
+		Traps: []Trap{
+			{TrapNo: 1, Result: &Result{Position: "2"}},
+			{TrapNo: 2, Result: &Result{Position: "1"}},
+			{TrapNo: 3, Result: &Result{Position: "1"}},
+		},
+	}
+	assert.Equal(t, [][]*Trap{{&race.Traps[1], &race.Traps[2]}}, race.DeadHeats())
+	assert.Equal(t, []*Trap{&race.Traps[1], &race.Traps[2]}, race.Winners())
+
+	race = testRace(t, "testdata/The Meadows/b201804143181070024.xml")
+	assert.Nil(t, race.DeadHeats())
+	assert.Nil(t, (&Race{}).Winners())
+}
+
+func TestDividendsLookup(t *testing.T) {
+	race := testRace(t, "testdata/The Meadows/b201804143181070024.xml")
+	require.NotNil(t, race.Dividends)
+
+	dividend, ok := race.Dividends.ForecastDividend(4, 7)
+	require.True(t, ok)
+	assert.Equal(t, makeDecimal(t, "51.15"), dividend)
+	_, ok = race.Dividends.ForecastDividend(7, 4)
+	assert.False(t, ok)
+
+	dividend, ok = race.Dividends.TricastDividend(4, 7, 1)
+	require.True(t, ok)
+	assert.Equal(t, makeDecimal(t, "107.82"), dividend)
+	_, ok = race.Dividends.TricastDividend(4, 1, 7)
+	assert.False(t, ok)
+}
+
+func TestRaceWinningForecast(t *testing.T) {
+	race := testRace(t, "testdata/The Meadows/b201804143181070024.xml")
+	dividend, traps, ok := race.WinningForecast()
+	require.True(t, ok)
+	assert.Equal(t, makeDecimal(t, "51.15"), dividend)
+	assert.Equal(t, []int{4, 7}, traps)
+
+	dividend, traps, ok = race.WinningTricast()
+	require.True(t, ok)
+	assert.Equal(t, makeDecimal(t, "107.82"), dividend)
+	assert.Equal(t, []int{4, 7, 1}, traps)
+
+	// dead heat for the third place
+	race = testRace(t, "testdata/Crayford/b2018041433736122020031.xml")
+	dividend, traps, ok = race.WinningForecast()
+	require.True(t, ok)
+	assert.Equal(t, makeDecimal(t, "16.14"), dividend)
+	assert.Equal(t, []int{4, 1}, traps)
+	_, _, ok = race.WinningTricast()
+	assert.False(t, ok)
+
+	_, _, ok = (&Race{}).WinningForecast()
+	assert.False(t, ok)
+}
+
+func TestLookups(t *testing.T) {
+	obj := parseTestFile(t, "testdata/Crayford/c20180414cra5_337361.xml")
+	require.Len(t, obj.Meetings, 1)
+	m := &obj.Meetings[0]
+
+	race, ok := m.RaceByNumber(3)
+	require.True(t, ok)
+	assert.Equal(t, 3, race.RaceNumber)
+	_, ok = m.RaceByNumber(99)
+	assert.False(t, ok)
+
+	trap, ok := race.TrapByNumber(2)
+	require.True(t, ok)
+	assert.Equal(t, 2, trap.TrapNo)
+	_, ok = race.TrapByNumber(9)
+	assert.False(t, ok)
+
+	require.NotNil(t, trap.Dog)
+	dog, ok := race.DogByID(trap.Dog.ID)
+	require.True(t, ok)
+	assert.Equal(t, trap.Dog, dog)
+	_, ok = race.DogByID(-1)
+	assert.False(t, ok)
+}
+
+func TestTrapLatestShow(t *testing.T) {
+	at := func(s string) time.Time {
+		ts, err := time.Parse("150405", s)
+		require.NoError(t, err)
+		return ts
+	}
+	trap := Trap{
+		Shows: []Show{
+			{TimeStamp: at("192400"), Price: &Price{Fractional: *big.NewRat(5, 1)}},
+			{TimeStamp: at("192300"), Price: &Price{Fractional: *big.NewRat(6, 1)}},
+			{TimeStamp: at("192500"), NoOffers: true},
+			{TimeStamp: at("192600"), MarketNumber: intPtr(2), Price: &Price{Fractional: *big.NewRat(4, 1)}},
+		},
+	}
+
+	show, ok := trap.LatestShow(1)
+	require.True(t, ok)
+	assert.Equal(t, at("192400"), show.TimeStamp)
+	price, ok := trap.CurrentPrice(0)
+	require.True(t, ok)
+	assert.Equal(t, "5/1", price.FractionalString())
+
+	price, ok = trap.CurrentPrice(2)
+	require.True(t, ok)
+	assert.Equal(t, "4/1", price.FractionalString())
+
+	_, ok = trap.LatestShow(3)
+	assert.False(t, ok)
+	_, ok = (&Trap{}).CurrentPrice(1)
+	assert.False(t, ok)
+
+	race := testRace(t, "testdata/Crayford/b2018041433736119270028.xml")
+	price, ok = race.Traps[0].CurrentPrice(1)
+	require.True(t, ok)
+	assert.Equal(t, race.Traps[0].Shows[len(race.Traps[0].Shows)-1].Price, price)
+}
+
+func TestShowMarket(t *testing.T) {
+	tests := []struct {
+		xml    string
+		number *int
+		market int
+	}{
+		{
+			xml:    `<Show timeStamp="20180414T124546+0000" noOffers="Yes"/>`,
+			number: nil,
+			market: 1,
+		},
+		{
+			xml:    `<Show timeStamp="20180414T124546+0000" marketNumber="2" noOffers="Yes"/>`,
+			number: intPtr(2),
+			market: 2,
+		},
+		{
+			xml:    `<Show timeStamp="20180414T124546+0000" marketNumber="0" noOffers="Yes"/>`,
+			number: intPtr(0),
+			market: 1,
+		},
+	}
+
+	for _, test := range tests {
+		var show xmlShow
+		require.NoError(t, xml.Unmarshal([]byte(test.xml), &show), test.xml)
+		assert.Equal(t, test.number, show.MarketNumber, test.xml)
+		assert.Equal(t, test.market, Show(show).Market(), test.xml)
+	}
+}
+
+func TestExpectedTimeDuration(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		err      bool
+	}{
+		{value: "28.45", expected: 28*time.Second + 450*time.Millisecond},
+		{value: "0128.45", expected: time.Minute + 28*time.Second + 450*time.Millisecond},
+		{value: "", err: true},
+		{value: "fast", err: true},
+	}
+
+	for _, test := range tests {
+		d, err := ExpectedTime{Type: "final", Value: test.value}.Duration()
+		if test.err {
+			assert.Error(t, err, test.value)
+			continue
+		}
+		require.NoError(t, err, test.value)
+		assert.Equal(t, test.expected, d, test.value)
+	}
+}
+
+func TestRaceFastestExpected(t *testing.T) {
+	dog := func(id int, times ...ExpectedTime) *Dog {
+		return &Dog{ID: id, ExpectedTimes: times}
+	}
+	race := Race{
+		Traps: []Trap{
+			{TrapNo: 1, Dog: dog(1, ExpectedTime{Type: "sectional", Value: "3.50"}, ExpectedTime{Type: "final", Value: "24.80"})},
+			{TrapNo: 2, Dog: dog(2, ExpectedTime{Type: "Final", Value: "24.65"})},
+			{TrapNo: 3, Dog: dog(3)},
+			{TrapNo: 4, Vacant: true},
+			{TrapNo: 5, Dog: dog(5, ExpectedTime{Type: "final", Value: "24.65"})},
+			{TrapNo: 6, Dog: dog(6, ExpectedTime{Type: "final", Value: "n/a"})},
+		},
+	}
+
+	var traps []int
+	for _, trap := range race.FastestExpected() {
+		traps = append(traps, trap.TrapNo)
+	}
+	assert.Equal(t, []int{2, 5}, traps)
+
+	behind, ok := race.ExpectedTimeBehind(1)
+	require.True(t, ok)
+	assert.Equal(t, 150*time.Millisecond, behind)
+	behind, ok = race.ExpectedTimeBehind(5)
+	require.True(t, ok)
+	assert.Zero(t, behind)
+	for _, trapNo := range []int{3, 4, 6, 7} {
+		_, ok = race.ExpectedTimeBehind(trapNo)
+		assert.False(t, ok, trapNo)
+	}
+	assert.Empty(t, (&Race{}).FastestExpected())
+}
+
+func TestDogFormSummary(t *testing.T) {
+	dr := parseTestFile(t, "testdata/Crayford/c20180414cra5_337361.xml")
+	race := dr.Meetings[0].Races[0]
+	require.Equal(t, 380, race.Distance)
+
+	tests := []struct {
+		trap     int
+		expected FormSummary
+	}{
+		{
+			trap: 1, // Clonmannon Lady
+			expected: FormSummary{
+				Runs:          5,
+				Wins:          1,
+				Places:        2,
+				DistanceRuns:  3,
+				DistanceWins:  1,
+				WinPercentage: 100.0 / 3,
+				BestTime:      24110 * time.Millisecond,
+			},
+		},
+		{
+			trap: 2, // Kelva Matty
+			expected: FormSummary{
+				Runs:         5,
+				Places:       3,
+				DistanceRuns: 5,
+				BestTime:     24120 * time.Millisecond,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		trap, ok := race.TrapByNumber(test.trap)
+		require.True(t, ok)
+		require.NotNil(t, trap.Dog)
+		summary := trap.Dog.FormSummary(race.Distance)
+		assert.InDelta(t, test.expected.WinPercentage, summary.WinPercentage, 1e-9, test.trap)
+		summary.WinPercentage = test.expected.WinPercentage
+		assert.Equal(t, test.expected, summary, test.trap)
+	}
+}
+
+func TestDogFormSummaryNotFinished(t *testing.T) {
+	form := func(position string, runTime time.Duration) FormRace {
+		return FormRace{
+			Distance: 480,
+			FormTraps: []FormTrap{
+				{Trap: 1, Dog: &Dog{ID: 2}, Result: &Result{Position: "1", RunTime: 28 * time.Second}},
+				{Trap: 3, Dog: &Dog{ID: 1}, Result: &Result{Position: position, RunTime: runTime}},
+			},
+		}
+	}
+	dog := Dog{
+		ID: 1,
+		FormRaces: []FormRace{
+			form("DN", 0),
+			form("DSQ", 28*time.Second),
+			form("2", 29*time.Second),
+			{Distance: 480, FormTraps: []FormTrap{{Trap: 1, Dog: &Dog{ID: 2}}}},
+		},
+	}
+
+	assert.Equal(t, FormSummary{
+		Runs:         3,
+		Places:       1,
+		DistanceRuns: 3,
+		BestTime:     28 * time.Second,
+	}, dog.FormSummary(480))
+	assert.Equal(t, FormSummary{Runs: 3, Places: 1}, dog.FormSummary(500))
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/xml"
 	"fmt"
 	"math/big"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/advbet/decimal"
@@ -31,6 +33,22 @@ type TrapSeeding string
 // DogSex is the sex of a dog
 type DogSex string
 
+// WithdrawalReason is the reason of dog withdrawal
+type WithdrawalReason string
+
+// GoingAllowance is the raw going allowance of a race, a signed number of
+// hundredths of a second per dog run time, e.g. "-10" or "N" for normal going.
+type GoingAllowance string
+
+// GreyhoundGrade is a structured race class, e.g. "A7" is letter grade "A" and
+// numeric grade 7. Open, trial and other ungraded races keep only the raw
+// class.
+type GreyhoundGrade struct {
+	Raw    string // The class as sent by PA, e.g. "A7" or "OR"
+	Letter string // Letter grade, e.g. "A", empty if class is not a grade
+	Number int    // Numeric grade, zero if class has no number
+}
+
 // xmlTimeElement is a date value with cusom XML unmarshaler that reads ISO 8601:1988
 // date value.
 type xmlTimeElement time.Time
@@ -48,6 +66,8 @@ type DogRacing struct {
 	Meetings []Meeting // The meeting(s)
 }
 
+type xmlDogRacing DogRacing
+
 // Meeting object describes a greyhound racing meeting with information on zero
 // or more races.
 type Meeting struct {
@@ -74,7 +94,7 @@ type Race struct {
 	Distance   int           // The distance of the race (metres)
 	Title      string        // The title of the race.
 	Prizes     string        // The prizes awarded for the race
-	OffTime    time.Time     // The time that the race actually started
+	OffTime    *time.Time    // The time that the race actually started, nil if the race is not off yet
 	Going      string        // The going allowance for this race
 	WinTime    time.Duration // The time taken to complete the race
 	State      RaceState     // The current state of this race
@@ -117,8 +137,9 @@ type xmlComment Comment
 
 // NonRunner contains details of the trap that is a nonrunner
 type NonRunner struct {
-	Trap   int    // The number of the trap this dog was due to start from
-	Reason string // Reason for dog withdrawal
+	Trap       int              // The number of the trap this dog was due to start from
+	Reason     WithdrawalReason // Reason for dog withdrawal
+	ReasonText string           // Raw withdrawal reason, set only if Reason is WithdrawalUnknown
 
 	Dog *Dog // Details of the dog that is a nonrunner
 }
@@ -251,7 +272,9 @@ type Result struct {
 	Weight        float64       // The weight of the dog (kilograms)
 	AdjustedTime  time.Duration // The finishing time adjusted for going and handicap.
 
-	StartingPrice *Price // The price returned for this dog
+	MarketPosition int    // Position in starting price market, 1 = favourite, 2 = 2nd favourite etc.
+	MarketCount    int    // Number sharing this position in market (2 = jt, 3 = co etc)
+	StartingPrice  *Price // The price returned for this dog
 }
 
 type xmlResult Result
@@ -259,7 +282,7 @@ type xmlResult Result
 // Show contains the details of a single show
 type Show struct {
 	TimeStamp    time.Time // The time at which the show was available
-	MarketNumber int       // When more than one betting market has been formed, this attribute indicates which market the show is applicable to, otherwise it will be absent.
+	MarketNumber *int      // When more than one betting market has been formed, this attribute indicates which market the show is applicable to, otherwise it will be absent (nil).
 	NoOffers     bool      // If no show price is currently being offered then this will be true
 
 	Price *Price // Show price. Absent only if noOffers attribute is true.
@@ -271,6 +294,8 @@ type xmlShow Show
 type Price struct {
 	Decimal    decimal.Number // Decimal representation of the price (empty or in HK format)
 	Fractional big.Rat        // Fractional representation of the price
+
+	zeroDenominator bool // Whether fraction with zero denominator was received
 }
 
 type xmlPrice Price
@@ -357,6 +382,7 @@ const (
 
 // List of allowed TrapSeeding values.
 const (
+	SeedingNone  TrapSeeding = ""      // Trap is not seeded (track does not seed)
 	SeedingWide  TrapSeeding = "Wide"  // Wide trap seeding
 	SeedingMid   TrapSeeding = "Mid"   // Mid trap seeding
 	SeedingRails TrapSeeding = "Rails" // Rails trap seeding
@@ -368,8 +394,58 @@ const (
 	SexBitch DogSex = "b" // Bitch
 )
 
-// UnmarshalXML implements xml.Unmarshaler interface.
+// List of allowed WithdrawalReason values.
+const (
+	WithdrawalNone            WithdrawalReason = ""                 // No reason given
+	WithdrawalVet             WithdrawalReason = "Vet"              // Withdrawn on veterinary advice
+	WithdrawalTrainer         WithdrawalReason = "Trainer"          // Withdrawn by the trainer
+	WithdrawalGoing           WithdrawalReason = "Going"            // Withdrawn due to the going
+	WithdrawalLame            WithdrawalReason = "Lame"             // The dog is lame
+	WithdrawalInjured         WithdrawalReason = "Injured"          // The dog is injured
+	WithdrawalOffColour       WithdrawalReason = "Off Colour"       // The dog is off colour
+	WithdrawalInSeason        WithdrawalReason = "In Season"        // The bitch is in season
+	WithdrawalWeightVariation WithdrawalReason = "Weight Variation" // The dog weight varies too much from the previous run
+
+	// WithdrawalUnknown is used for reasons not listed above, the raw
+	// value is kept in NonRunner.ReasonText.
+	WithdrawalUnknown WithdrawalReason = "Unknown"
+)
+
+// UnmarshalXML implements xml.Unmarshaler interface. Unknown message type,
+// meeting state, race type or race state fails the document, see
+// ParseFileWithWarnings for lenient parsing.
 func (r *DogRacing) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if err := (*xmlDogRacing)(r).UnmarshalXML(d, start); err != nil {
+		return err
+	}
+	return r.checkEnums()
+}
+
+// checkEnums returns an error if document has unknown message type, meeting
+// state, race type or race state.
+func (r *DogRacing) checkEnums() error {
+	if !r.Type.isValid() {
+		return fmt.Errorf("invalid Message type attibute value: %s", r.Type)
+	}
+	for _, m := range r.Meetings {
+		if !m.State.isValid() {
+			return fmt.Errorf("meeting %d: invalid Meeting state attibute value: %s", m.MeetingID, m.State)
+		}
+		for _, race := range m.Races {
+			if !race.Type.isValid() {
+				return fmt.Errorf("meeting %d: race %d: invalid Race type attibute value: %s", m.MeetingID, race.RaceNumber, race.Type)
+			}
+			if !race.State.isValid() {
+				return fmt.Errorf("meeting %d: race %d: invalid Race state attibute value: %s", m.MeetingID, race.RaceNumber, race.State)
+			}
+		}
+	}
+	return nil
+}
+
+// UnmarshalXML implements xml.Unmarshaler interface. Enum values are not
+// checked.
+func (r *xmlDogRacing) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	var data struct {
 		Type  MessageType `xml:"type,attr"`
 		State string      `xml:"state,attr"`
@@ -379,14 +455,11 @@ func (r *DogRacing) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	if err := d.DecodeElement(&data, &start); err != nil {
 		return err
 	}
-	if !data.Type.isValid() {
-		return fmt.Errorf("invalid Message type attibute value: %s", data.Type)
-	}
 	var meetings []Meeting
 	for _, m := range data.Meetings {
 		meetings = append(meetings, Meeting(m))
 	}
-	*r = DogRacing{
+	*r = xmlDogRacing{
 		Type:     data.Type,
 		State:    data.State,
 		Meetings: meetings,
@@ -412,18 +485,17 @@ func (m *xmlMeeting) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 		State: MeetingDormant,
 	}
 	if err := d.DecodeElement(&data, &start); err != nil {
-		return err
-	}
-	if !data.State.isValid() {
-		return fmt.Errorf("invalid Meeting state attibute value: %s", data.State)
+		return fmt.Errorf("meeting %d: %w", data.MeetingID, err)
 	}
 	var races []Race
 	for _, r := range data.Races {
 		if r.Time.Year() == 0 { // Get full date
 			r.Time = addDate(r.Time, time.Time(data.Date))
 		}
-		if r.OffTime.Year() == 0 { // Get full date
-			r.OffTime = addDate(r.OffTime, time.Time(data.Date))
+		// Absent off time is left nil, only present one gets the date.
+		if r.OffTime != nil && r.OffTime.Year() == 0 { // Get full date
+			offTime := addDate(*r.OffTime, time.Time(data.Date))
+			r.OffTime = &offTime
 		}
 
 		for i, t := range r.Traps {
@@ -457,21 +529,21 @@ func (m *xmlMeeting) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 // UnmarshalXML implements xml.Unmarshaler interface.
 func (r *xmlRace) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	data := struct {
-		Revision   int            `xml:"revision,attr"`
-		RaceNumber int            `xml:"raceNumber,attr"`
-		Time       xmlTimeElement `xml:"time,attr"`
-		Type       RaceType       `xml:"type,attr"`
-		Handicap   xmlYesNo       `xml:"handicap,attr"`
-		Class      string         `xml:"class,attr"`
-		Distance   int            `xml:"distance,attr"`
-		Title      string         `xml:"title,attr"`
-		Prizes     string         `xml:"prizes,attr"`
-		OffTime    xmlTimeElement `xml:"offTime,attr"`
-		Going      string         `xml:"going,attr"`
-		WinTime    string         `xml:"winTime,attr"`
-		State      RaceState      `xml:"state,attr"`
-		Bags       xmlYesNo       `xml:"Bags,attr"`
-		Tricast    xmlYesNo       `xml:"tricast,attr"`
+		Revision   int             `xml:"revision,attr"`
+		RaceNumber int             `xml:"raceNumber,attr"`
+		Time       xmlTimeElement  `xml:"time,attr"`
+		Type       RaceType        `xml:"type,attr"`
+		Handicap   xmlYesNo        `xml:"handicap,attr"`
+		Class      string          `xml:"class,attr"`
+		Distance   int             `xml:"distance,attr"`
+		Title      string          `xml:"title,attr"`
+		Prizes     string          `xml:"prizes,attr"`
+		OffTime    *xmlTimeElement `xml:"offTime,attr"`
+		Going      string          `xml:"going,attr"`
+		WinTime    string          `xml:"winTime,attr"`
+		State      RaceState       `xml:"state,attr"`
+		Bags       xmlYesNo        `xml:"Bags,attr"`
+		Tricast    xmlYesNo        `xml:"tricast,attr"`
 		Comments   struct {
 			Comments []xmlComment `xml:"Comment"`
 		} `xml:"Comments"`
@@ -483,18 +555,12 @@ func (r *xmlRace) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	}
 
 	if err := d.DecodeElement(&data, &start); err != nil {
-		return err
-	}
-	if !data.Type.isValid() {
-		return fmt.Errorf("invalid Race type attibute value: %s", data.Type)
-	}
-	if !data.State.isValid() {
-		return fmt.Errorf("invalid Race state attibute value: %s", data.State)
+		return fmt.Errorf("race %d: %w", data.RaceNumber, err)
 	}
 
 	winTime, err := parseDuration(data.WinTime)
 	if err != nil {
-		return err
+		return fmt.Errorf("race %d: %w", data.RaceNumber, err)
 	}
 	var comments []Comment
 	for _, c := range data.Comments.Comments {
@@ -518,7 +584,7 @@ func (r *xmlRace) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		Distance:   data.Distance,
 		Title:      data.Title,
 		Prizes:     data.Prizes,
-		OffTime:    time.Time(data.OffTime),
+		OffTime:    (*time.Time)(data.OffTime),
 		Going:      data.Going,
 		WinTime:    winTime,
 		State:      data.State,
@@ -532,29 +598,55 @@ func (r *xmlRace) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	return nil
 }
 
-// UnmarshalXML implements xml.Unmarshaler interface.
-func (t *xmlTrap) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	var data struct {
-		TrapNo   int         `xml:"trap,attr"`
-		Vacant   xmlYesNo    `xml:"vacant,attr"`
-		Wide     xmlYesNo    `xml:"wide,attr"`
-		Seeding  TrapSeeding `xml:"seeding,attr"`
-		Handicap string      `xml:"handicap,attr"`
-		Reserve  xmlYesNo    `xml:"reserve,attr"`
-		Photo    int         `xml:"photo,attr"`
+// xmlTrapData is the decoded Trap element. Traps are among the most frequent
+// elements, decoded elements are pooled to reduce allocations, see
+// xmlTrap.UnmarshalXML.
+type xmlTrapData struct {
+	TrapNo   int         `xml:"trap,attr"`
+	Vacant   xmlYesNo    `xml:"vacant,attr"`
+	Wide     xmlYesNo    `xml:"wide,attr"`
+	Seeding  TrapSeeding `xml:"seeding,attr"`
+	Handicap string      `xml:"handicap,attr"`
+	Reserve  xmlYesNo    `xml:"reserve,attr"`
+	Photo    int         `xml:"photo,attr"`
+
+	Dog    *xmlDog    `xml:"Dog"`
+	Shows  []xmlShow  `xml:"Show"`
+	Result *xmlResult `xml:"Result"`
+}
+
+var trapDataPool = sync.Pool{New: func() interface{} { return new(xmlTrapData) }}
 
-		Dog    *xmlDog    `xml:"Dog"`
-		Shows  []xmlShow  `xml:"Show"`
-		Result *xmlResult `xml:"Result"`
+// reset clears decoded trap keeping capacity of the shows slice.
+func (t *xmlTrapData) reset() {
+	for i := range t.Shows {
+		t.Shows[i] = xmlShow{}
 	}
-	if err := d.DecodeElement(&data, &start); err != nil {
-		return err
+	*t = xmlTrapData{Shows: t.Shows[:0]}
+}
+
+// UnmarshalXML implements xml.Unmarshaler interface.
+func (t *xmlTrap) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	data := trapDataPool.Get().(*xmlTrapData)
+	defer func() {
+		data.reset()
+		trapDataPool.Put(data)
+	}()
+	if err := d.DecodeElement(data, &start); err != nil {
+		return fmt.Errorf("trap %d: %w", data.TrapNo, err)
 	}
 
 	var shows []Show
+	if len(data.Shows) > 0 {
+		shows = make([]Show, 0, len(data.Shows))
+	}
 	for _, s := range data.Shows {
 		shows = append(shows, Show(s))
 	}
+	// shows usually arrive in chronological order, but it is not guaranteed
+	sort.SliceStable(shows, func(i, j int) bool {
+		return shows[i].TimeStamp.Before(shows[j].TimeStamp)
+	})
 	*t = xmlTrap{
 		TrapNo:   data.TrapNo,
 		Vacant:   bool(data.Vacant),
@@ -570,32 +662,43 @@ func (t *xmlTrap) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	return nil
 }
 
+// xmlDogData is the decoded Dog element. Dogs are among the most frequent
+// elements, decoded elements are pooled to reduce allocations, see
+// xmlDog.UnmarshalXML.
+type xmlDogData struct {
+	ID     int    `xml:"id,attr"`
+	Name   string `xml:"name,attr"`
+	Origin string `xml:"origin,attr"`
+
+	BestTime      *xmlBestTime `xml:"BestTime"`
+	ExpectedTimes struct {
+		ExpectedTimes []xmlExpectedTime `xml:"ExpectedTime"`
+	} `xml:"ExpectedTimes"`
+	Breeding      *xmlBreeding `xml:"Breeding"`
+	Trainer       xmlTrainer   `xml:"Trainer"`
+	Owner         xmlOwner     `xml:"Owner"`
+	Ratings       []xmlRating  `xml:"Rating"`
+	Comments      []xmlComment `xml:"Comment"`
+	ForecastPrice struct {
+		Source string    `xml:"source,attr"`
+		Price  *xmlPrice `xml:"Price"`
+	} `xml:"ForecastPrice"`
+	Form struct {
+		FormRaces []xmlFormRace `xml:"FormRace"`
+	} `xml:"Form"`
+}
+
+var dogDataPool = sync.Pool{New: func() interface{} { return new(xmlDogData) }}
+
 // UnmarshalXML implements xml.Unmarshaler interface.
 func (e *xmlDog) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	var data struct {
-		ID     int    `xml:"id,attr"`
-		Name   string `xml:"name,attr"`
-		Origin string `xml:"origin,attr"`
-
-		BestTime      *xmlBestTime `xml:"BestTime"`
-		ExpectedTimes struct {
-			ExpectedTimes []xmlExpectedTime `xml:"ExpectedTime"`
-		} `xml:"ExpectedTimes"`
-		Breeding      *xmlBreeding `xml:"Breeding"`
-		Trainer       xmlTrainer   `xml:"Trainer"`
-		Owner         xmlOwner     `xml:"Owner"`
-		Ratings       []xmlRating  `xml:"Rating"`
-		Comments      []xmlComment `xml:"Comment"`
-		ForecastPrice struct {
-			Source string    `xml:"source,attr"`
-			Price  *xmlPrice `xml:"Price"`
-		} `xml:"ForecastPrice"`
-		Form struct {
-			FormRaces []xmlFormRace `xml:"FormRace"`
-		} `xml:"Form"`
-	}
-	if err := d.DecodeElement(&data, &start); err != nil {
-		return err
+	data := dogDataPool.Get().(*xmlDogData)
+	defer func() {
+		*data = xmlDogData{}
+		dogDataPool.Put(data)
+	}()
+	if err := d.DecodeElement(data, &start); err != nil {
+		return fmt.Errorf("dog %d: %w", data.ID, err)
 	}
 
 	var expectedTimes []ExpectedTime
@@ -611,6 +714,9 @@ func (e *xmlDog) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		comments = append(comments, Comment(c))
 	}
 	var formRaces []FormRace
+	if len(data.Form.FormRaces) > 0 {
+		formRaces = make([]FormRace, 0, len(data.Form.FormRaces))
+	}
 	for _, r := range data.Form.FormRaces {
 		formRaces = append(formRaces, FormRace(r))
 	}
@@ -637,7 +743,7 @@ func (c *xmlComment) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 	var data struct {
 		Source  string `xml:"source,attr"`
 		Type    string `xml:"type,attr"`
-		Comment string `xml:",innerxml"`
+		Comment string `xml:",chardata"`
 	}
 	if err := d.DecodeElement(&data, &start); err != nil {
 		return err
@@ -662,10 +768,12 @@ func (e *xmlNonRunner) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 		return err
 	}
 
+	reason, reasonText := parseWithdrawalReason(data.Reason)
 	*e = xmlNonRunner{
-		Trap:   data.Trap,        // The number of the trap this dog was due to start from
-		Reason: data.Reason,      // Reason for dog withdrawal
-		Dog:    (*Dog)(data.Dog), // Details of the dog that is a nonrunner
+		Trap:       data.Trap,        // The number of the trap this dog was due to start from
+		Reason:     reason,           // Reason for dog withdrawal
+		ReasonText: reasonText,       // Raw withdrawal reason, set only if Reason is WithdrawalUnknown
+		Dog:        (*Dog)(data.Dog), // Details of the dog that is a nonrunner
 	}
 	return nil
 }
@@ -904,29 +1012,43 @@ func (r *xmlResult) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		return err
 	}
 	*r = xmlResult{
-		Position:      data.Position,                      // The finish position of the dog
-		BtnDistance:   data.BtnDistance,                   // If the Result element is contained within a FormTrap Element this is the distance between this dog and the winner. If the Result element is contained within a Trap Element this is the distance between this dog and the dog in front
-		SectionalTime: sectionalTime,                      // The time taken to reach the first bend.
-		BendPosition:  data.BendPosition,                  // The dog's position at each bend.
-		RunComment:    data.RunComment,                    // The description of how the dog ran
-		RunTime:       runTime,                            // The time taken by this dog to run the race
-		Weight:        data.Weight,                        // The weight of the dog (kilograms)
-		AdjustedTime:  adjustedTime,                       // The finishing time adjusted for going and handicap.
-		StartingPrice: (*Price)(data.StartingPrice.Price), // The price returned for this dog
+		Position:      data.Position,     // The finish position of the dog
+		BtnDistance:   data.BtnDistance,  // If the Result element is contained within a FormTrap Element this is the distance between this dog and the winner. If the Result element is contained within a Trap Element this is the distance between this dog and the dog in front
+		SectionalTime: sectionalTime,     // The time taken to reach the first bend.
+		BendPosition:  data.BendPosition, // The dog's position at each bend.
+		RunComment:    data.RunComment,   // The description of how the dog ran
+		RunTime:       runTime,           // The time taken by this dog to run the race
+		Weight:        data.Weight,       // The weight of the dog (kilograms)
+		AdjustedTime:  adjustedTime,      // The finishing time adjusted for going and handicap.
+
+		MarketPosition: data.StartingPrice.MarketPos,       // Position in starting price market, 1 = favourite, 2 = 2nd favourite etc.
+		MarketCount:    data.StartingPrice.MarketCnt,       // Number sharing this position in market (2 = jt, 3 = co etc)
+		StartingPrice:  (*Price)(data.StartingPrice.Price), // The price returned for this dog
 	}
 	return nil
 }
 
+// xmlShowData is the decoded Show element. Shows are the most frequent
+// elements, decoded elements are pooled to reduce allocations, see
+// xmlShow.UnmarshalXML.
+type xmlShowData struct {
+	TimeStamp    xmlTimeElement `xml:"timeStamp,attr"`
+	MarketNumber *int           `xml:"marketNumber,attr"`
+	NoOffers     xmlYesNo       `xml:"noOffers,attr"`
+
+	Price *xmlPrice `xml:"Price"`
+}
+
+var showDataPool = sync.Pool{New: func() interface{} { return new(xmlShowData) }}
+
 // UnmarshalXML implements xml.Unmarshaler interface.
 func (s *xmlShow) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	var data struct {
-		TimeStamp    xmlTimeElement `xml:"timeStamp,attr"`
-		MarketNumber int            `xml:"marketNumber,attr"`
-		NoOffers     xmlYesNo       `xml:"noOffers,attr"`
-
-		Price *xmlPrice `xml:"Price"`
-	}
-	if err := d.DecodeElement(&data, &start); err != nil {
+	data := showDataPool.Get().(*xmlShowData)
+	defer func() {
+		*data = xmlShowData{}
+		showDataPool.Put(data)
+	}()
+	if err := d.DecodeElement(data, &start); err != nil {
 		return err
 	}
 
@@ -945,19 +1067,25 @@ func (p *xmlPrice) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	var data struct {
 		Decimal     decimal.Number `xml:"decimal,attr"`
 		Numerator   int            `xml:"numerator,attr"`
-		Denominator int            `xml:"denominator,attr"`
+		Denominator *int           `xml:"denominator,attr"`
 	}
 	if err := d.DecodeElement(&data, &start); err != nil {
 		return err
 	}
 
 	var fraction big.Rat
-	if data.Denominator != 0 {
-		fraction = *big.NewRat(int64(data.Numerator), int64(data.Denominator))
+	switch {
+	case data.Denominator == nil:
+		// decimal only price (e.g. Australian tracks), fraction is left
+		// empty, see Price.Fraction
+	case *data.Denominator != 0:
+		fraction = *big.NewRat(int64(data.Numerator), int64(*data.Denominator))
 	}
 	*p = xmlPrice{
 		Decimal:    data.Decimal, // Decimal representation of the price
 		Fractional: fraction,     // Fractional representation of the price
+
+		zeroDenominator: data.Denominator != nil && *data.Denominator == 0,
 	}
 	return nil
 }
@@ -1137,6 +1265,60 @@ func (s RaceType) isValid() bool {
 	}
 }
 
+func (s TrapSeeding) isValid() bool {
+	switch s {
+	case SeedingNone,
+		SeedingWide,
+		SeedingMid,
+		SeedingRails:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s WithdrawalReason) isValid() bool {
+	switch s {
+	case WithdrawalNone,
+		WithdrawalVet,
+		WithdrawalTrainer,
+		WithdrawalGoing,
+		WithdrawalLame,
+		WithdrawalInjured,
+		WithdrawalOffColour,
+		WithdrawalInSeason,
+		WithdrawalWeightVariation:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseWithdrawalReason converts feed withdrawal reason to WithdrawalReason
+// value, reasons are matched case insensitively. Unrecognised reasons are
+// returned as WithdrawalUnknown together with the raw value.
+func parseWithdrawalReason(s string) (WithdrawalReason, string) {
+	reason := WithdrawalReason(strings.TrimSpace(s))
+	if reason.isValid() {
+		return reason, ""
+	}
+	for _, r := range []WithdrawalReason{
+		WithdrawalVet,
+		WithdrawalTrainer,
+		WithdrawalGoing,
+		WithdrawalLame,
+		WithdrawalInjured,
+		WithdrawalOffColour,
+		WithdrawalInSeason,
+		WithdrawalWeightVariation,
+	} {
+		if strings.EqualFold(string(reason), string(r)) {
+			return r, ""
+		}
+	}
+	return WithdrawalUnknown, s
+}
+
 // parseDudation converts ISO 8601:1988 mmss.ss formated string to golang
 // time.Duration value.
 func parseDuration(s string) (time.Duration, error) {
@@ -1,6 +1,7 @@
 package greyhounds
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
@@ -23,6 +24,14 @@ func makeDecimal(t *testing.T, s string) decimal.Number {
 	return d
 }
 
+func intPtr(i int) *int {
+	return &i
+}
+
+func timePtr(tm time.Time) *time.Time {
+	return &tm
+}
+
 func makeTime(t *testing.T, s string) time.Time {
 	tm, err := time.Parse(time.RFC3339, s)
 	if err != nil {
@@ -132,7 +141,7 @@ func TestParseGreyhoundRacing(t *testing.T) {
 										Shows: []Show{
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T19:21:56+01:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(6, 1),
 												},
@@ -151,7 +160,7 @@ func TestParseGreyhoundRacing(t *testing.T) {
 										Shows: []Show{
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T19:21:59+01:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(2, 1),
 												},
@@ -170,7 +179,7 @@ func TestParseGreyhoundRacing(t *testing.T) {
 										Shows: []Show{
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T19:22:04+01:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(2, 1),
 												},
@@ -189,7 +198,7 @@ func TestParseGreyhoundRacing(t *testing.T) {
 										Shows: []Show{
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T19:22:08+01:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(3, 1),
 												},
@@ -208,7 +217,7 @@ func TestParseGreyhoundRacing(t *testing.T) {
 										Shows: []Show{
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T19:22:13+01:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(5, 1),
 												},
@@ -227,7 +236,7 @@ func TestParseGreyhoundRacing(t *testing.T) {
 										Shows: []Show{
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T19:22:17+01:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(6, 1),
 												},
@@ -261,7 +270,7 @@ func TestParseGreyhoundRacing(t *testing.T) {
 								Handicap:   false,
 								Class:      "A",
 								Distance:   525,
-								OffTime:    makeTime(t, "2018-04-14T12:46:27+00:00"),
+								OffTime:    timePtr(makeTime(t, "2018-04-14T12:46:27+00:00")),
 								State:      RaceFinalResult,
 								Traps: []Trap{
 									Trap{
@@ -276,21 +285,23 @@ func TestParseGreyhoundRacing(t *testing.T) {
 										Shows: []Show{
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T12:44:25+00:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(8, 1),
 												},
 											},
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T12:45:46+00:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(9, 1),
 												},
 											},
 										},
 										Result: &Result{
-											Position: "0",
+											Position:       "0",
+											MarketPosition: 4,
+											MarketCount:    1,
 											StartingPrice: &Price{
 												Fractional: *big.NewRat(10, 1),
 											},
@@ -308,14 +319,16 @@ func TestParseGreyhoundRacing(t *testing.T) {
 										Shows: []Show{
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T12:44:32+00:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(10, 3),
 												},
 											},
 										},
 										Result: &Result{
-											Position: "0",
+											Position:       "0",
+											MarketPosition: 2,
+											MarketCount:    1,
 											StartingPrice: &Price{
 												Fractional: *big.NewRat(3, 1),
 											},
@@ -333,21 +346,23 @@ func TestParseGreyhoundRacing(t *testing.T) {
 										Shows: []Show{
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T12:44:35+00:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(17, 2),
 												},
 											},
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T12:45:50+00:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(10, 1),
 												},
 											},
 										},
 										Result: &Result{
-											Position: "2",
+											Position:       "2",
+											MarketPosition: 5,
+											MarketCount:    2,
 											StartingPrice: &Price{
 												Fractional: *big.NewRat(12, 1),
 											},
@@ -365,21 +380,23 @@ func TestParseGreyhoundRacing(t *testing.T) {
 										Shows: []Show{
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T12:44:41+00:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(11, 1),
 												},
 											},
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T12:45:52+00:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(12, 1),
 												},
 											},
 										},
 										Result: &Result{
-											Position: "0",
+											Position:       "0",
+											MarketPosition: 5,
+											MarketCount:    2,
 											StartingPrice: &Price{
 												Fractional: *big.NewRat(12, 1),
 											},
@@ -397,14 +414,16 @@ func TestParseGreyhoundRacing(t *testing.T) {
 										Shows: []Show{
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T12:44:45+00:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(3, 1),
 												},
 											},
 										},
 										Result: &Result{
-											Position: "0",
+											Position:       "0",
+											MarketPosition: 3,
+											MarketCount:    1,
 											StartingPrice: &Price{
 												Fractional: *big.NewRat(10, 3),
 											},
@@ -422,14 +441,16 @@ func TestParseGreyhoundRacing(t *testing.T) {
 										Shows: []Show{
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T12:44:48+00:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(6, 4),
 												},
 											},
 										},
 										Result: &Result{
-											Position: "1",
+											Position:       "1",
+											MarketPosition: 1,
+											MarketCount:    1,
 											StartingPrice: &Price{
 												Fractional: *big.NewRat(5, 4),
 											},
@@ -447,21 +468,23 @@ func TestParseGreyhoundRacing(t *testing.T) {
 										Shows: []Show{
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T12:44:51+00:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(33, 1),
 												},
 											},
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T12:45:57+00:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(40, 1),
 												},
 											},
 										},
 										Result: &Result{
-											Position: "0",
+											Position:       "0",
+											MarketPosition: 8,
+											MarketCount:    1,
 											StartingPrice: &Price{
 												Fractional: *big.NewRat(50, 1),
 											},
@@ -479,28 +502,30 @@ func TestParseGreyhoundRacing(t *testing.T) {
 										Shows: []Show{
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T12:44:54+00:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(16, 1),
 												},
 											},
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T12:45:59+00:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(18, 1),
 												},
 											},
 											Show{
 												TimeStamp:    makeTime(t, "2018-04-14T12:46:03+00:00"),
-												MarketNumber: 1,
+												MarketNumber: intPtr(1),
 												Price: &Price{
 													Fractional: *big.NewRat(20, 1),
 												},
 											},
 										},
 										Result: &Result{
-											Position: "3",
+											Position:       "3",
+											MarketPosition: 7,
+											MarketCount:    1,
 											StartingPrice: &Price{
 												Fractional: *big.NewRat(25, 1),
 											},
@@ -624,6 +649,12 @@ func TestParseDuration(t *testing.T) {
 		d, err := parseDuration(test.s)
 		assert.Equal(t, test.d, d, fmt.Sprintf("input: %s", test.s))
 		assert.Equal(t, test.err, err, fmt.Sprintf("input: %s", test.s))
+		if err != nil {
+			continue
+		}
+		d, err = parseDuration(FormatDuration(d))
+		require.NoError(t, err, fmt.Sprintf("input: %s", test.s))
+		assert.Equal(t, test.d, d, fmt.Sprintf("round trip: %s", test.s))
 	}
 }
 
@@ -650,3 +681,142 @@ func TestAddDate(t *testing.T) {
 		assert.Equal(t, test.expected, add)
 	}
 }
+
+func TestParseTrapShowsOrder(t *testing.T) {
+	var trap xmlTrap
+	require.NoError(t, xml.Unmarshal([]byte(`<Trap trap="1" vacant="No" wide="No" reserve="No">
+	<Show timeStamp="20180414T124546+0000" marketNumber="1"><Price numerator="9" denominator="1"/></Show>
+	<Show timeStamp="20180414T124425+0000" marketNumber="1"><Price numerator="8" denominator="1"/></Show>
+	<Show timeStamp="20180414T124425+0000" marketNumber="2"><Price numerator="7" denominator="1"/></Show>
+</Trap>`), &trap))
+	require.Len(t, trap.Shows, 3)
+	assert.Equal(t, "8/1", trap.Shows[0].Price.FractionalString())
+	assert.Equal(t, "7/1", trap.Shows[1].Price.FractionalString())
+	assert.Equal(t, "9/1", trap.Shows[2].Price.FractionalString())
+}
+
+func TestParseWithdrawalReason(t *testing.T) {
+	tests := []struct {
+		text     string
+		reason   WithdrawalReason
+		original string
+	}{
+		{text: "", reason: WithdrawalNone},
+		{text: "Off Colour", reason: WithdrawalOffColour},
+		{text: "In Season", reason: WithdrawalInSeason},
+		{text: "vet", reason: WithdrawalVet},
+		{text: "LAME", reason: WithdrawalLame},
+		{text: "Kennel Cough", reason: WithdrawalUnknown, original: "Kennel Cough"},
+	}
+
+	for _, test := range tests {
+		reason, original := parseWithdrawalReason(test.text)
+		assert.Equal(t, test.reason, reason, test.text)
+		assert.Equal(t, test.original, original, test.text)
+	}
+}
+
+func TestParseNonRunnerReason(t *testing.T) {
+	var nr xmlNonRunner
+	require.NoError(t, xml.Unmarshal([]byte(`<NonRunner trap="3" reasonForWithdrawal="Off Colour"/>`), &nr))
+	assert.Equal(t, WithdrawalOffColour, nr.Reason)
+	assert.Empty(t, nr.ReasonText)
+
+	require.NoError(t, xml.Unmarshal([]byte(`<NonRunner trap="3" reasonForWithdrawal="Kennel Cough"/>`), &nr))
+	assert.Equal(t, WithdrawalUnknown, nr.Reason)
+	assert.Equal(t, "Kennel Cough", nr.ReasonText)
+
+	// raw reason is written back
+	blob, err := xml.Marshal(nr)
+	require.NoError(t, err)
+	assert.Contains(t, string(blob), `reasonForWithdrawal="Kennel Cough"`)
+}
+
+func TestParseComment(t *testing.T) {
+	var c xmlComment
+	require.NoError(t, xml.Unmarshal([]byte(`<Comment source="easygate" type="verdict">Trifecta, &amp;.10 Super</Comment>`), &c))
+	assert.Equal(t, "easygate", c.Source)
+	assert.Equal(t, "verdict", c.Type)
+	assert.Equal(t, "Trifecta, &.10 Super", c.Text)
+
+	// text is escaped when written back
+	blob, err := xml.Marshal(c)
+	require.NoError(t, err)
+	assert.Equal(t, `<xmlComment source="easygate" type="verdict">Trifecta, &amp;.10 Super</xmlComment>`, string(blob))
+}
+
+const benchmarkTrapXML = `<Trap trap="1" vacant="No" wide="No" reserve="No">
+  <Dog id="24884" name="Dyna Maple"/>
+  <Show timeStamp="20180414T105018+0000" marketNumber="1"><Price numerator="15" denominator="2"/></Show>
+  <Show timeStamp="20180414T105345+0000" marketNumber="1"><Price numerator="8" denominator="1"/></Show>
+  <Show timeStamp="20180414T105646+0000" marketNumber="1"><Price numerator="17" denominator="2"/></Show>
+  <Show timeStamp="20180414T105714+0000" marketNumber="1"><Price numerator="9" denominator="1"/></Show>
+  <Result position="3" btnDistance="">
+    <StartingPrice marketPos="5" marketCnt="2"><Price numerator="9" denominator="1"/></StartingPrice>
+  </Result>
+</Trap>`
+
+func BenchmarkUnmarshalTrap(b *testing.B) {
+	blob := []byte(benchmarkTrapXML)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var trap xmlTrap
+		if err := xml.Unmarshal(blob, &trap); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalShow(b *testing.B) {
+	blob := []byte(`<Show timeStamp="20180414T105018+0000" marketNumber="1"><Price numerator="15" denominator="2"/></Show>`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var show xmlShow
+		if err := xml.Unmarshal(blob, &show); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalDog(b *testing.B) {
+	blob, err := ioutil.ReadFile("testdata/Crayford/c20180414cra5_337361.xml")
+	require.NoError(b, err)
+	start := bytes.Index(blob, []byte("<Dog "))
+	end := bytes.Index(blob, []byte("</Dog>"))
+	require.True(b, start >= 0 && end > start)
+	blob = blob[start : end+len("</Dog>")]
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var dog xmlDog
+		if err := xml.Unmarshal(blob, &dog); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkParseFile(b *testing.B, file string) {
+	blob, err := ioutil.ReadFile(file)
+	require.NoError(b, err)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(blob)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseFile(blob); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseFile(b *testing.B) {
+	for _, file := range []string{
+		"testdata/The Meadows/b201804143181060038.xml",
+		"testdata/Crayford/b2018041433736119270028.xml",
+		"testdata/Crayford/c20180414cra5_337361.xml",
+		"testdata/Wheeling Island/c20180414wli_3173.xml",
+	} {
+		b.Run(path.Base(file), func(b *testing.B) {
+			benchmarkParseFile(b, file)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package greyhounds
+
+import "sync"
+
+// Tracker consolidates successive race messages into the current state of the
+// races. Races are keyed by meeting ID and race number and combined using
+// MergeRace. Messages having a lower revision than the known race state are
+// ignored. Zero value is an empty tracker ready to use, it is safe for
+// concurrent use.
+type Tracker struct {
+	mu    sync.Mutex
+	races map[trackerKey]Race
+}
+
+// trackerKey identifies a race tracked by Tracker.
+type trackerKey struct {
+	meetingID  int
+	raceNumber int
+}
+
+// Apply updates the state of the race held at the given meeting. False is
+// returned if the race message is out of order and was ignored.
+func (t *Tracker) Apply(meetingID int, race Race) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.races == nil {
+		t.races = make(map[trackerKey]Race)
+	}
+	key := trackerKey{meetingID: meetingID, raceNumber: race.RaceNumber}
+	known, ok := t.races[key]
+	if !ok {
+		t.races[key] = race
+		return true
+	}
+	if race.Revision < known.Revision {
+		return false
+	}
+	t.races[key] = MergeRace(known, race)
+	return true
+}
+
+// ApplyFile updates the state of all the races in the document, see Apply.
+func (t *Tracker) ApplyFile(f *DogRacing) {
+	for _, m := range f.Meetings {
+		for _, r := range m.Races {
+			t.Apply(m.MeetingID, r)
+		}
+	}
+}
+
+// Race returns the current state of the race. False is returned if no message
+// of the race was applied yet. Returned race shares its slices and pointers
+// with the tracker state and with races passed to Apply, it must be treated as
+// read only. Races are never modified in place by Apply, so the returned race
+// stays unchanged after later messages are applied.
+func (t *Tracker) Race(meetingID, raceNumber int) (Race, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	race, ok := t.races[trackerKey{meetingID: meetingID, raceNumber: raceNumber}]
+	return race, ok
+}
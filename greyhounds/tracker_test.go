@@ -0,0 +1,52 @@
+package greyhounds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	var tracker Tracker
+	for _, file := range []string{
+		"testdata/Crayford/b2018041433736119270007.xml",
+		"testdata/Crayford/b2018041433736119270028.xml",
+		"testdata/Crayford/b201804143373611943.xml",
+	} {
+		tracker.ApplyFile(parseTestFile(t, file))
+	}
+
+	// lower revision received out of order is ignored
+	stale := testRace(t, "testdata/Crayford/b2018041433736119270001.xml")
+	assert.False(t, tracker.Apply(337361, stale))
+
+	race, ok := tracker.Race(337361, 1)
+	require.True(t, ok)
+	assert.Equal(t, 28, race.Revision)
+	assert.Equal(t, RaceFinalResult, race.State)
+
+	race, ok = tracker.Race(337361, 2)
+	require.True(t, ok)
+	assert.Equal(t, 2, race.RaceNumber)
+
+	_, ok = tracker.Race(337361, 3)
+	assert.False(t, ok)
+	_, ok = tracker.Race(1, 1)
+	assert.False(t, ok)
+}
+
+func TestTrackerRaceUnchangedByApply(t *testing.T) {
+	var tracker Tracker
+	tracker.Apply(337361, testRace(t, "testdata/Crayford/b2018041433736119270007.xml"))
+	race, ok := tracker.Race(337361, 1)
+	require.True(t, ok)
+
+	for _, file := range []string{
+		"testdata/Crayford/b2018041433736119270008.xml",
+		"testdata/Crayford/b2018041433736119270028.xml",
+	} {
+		require.True(t, tracker.Apply(337361, testRace(t, file)))
+	}
+	assert.Equal(t, testRace(t, "testdata/Crayford/b2018041433736119270007.xml"), race)
+}
@@ -1,10 +1,15 @@
 package greyhounds
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/advbet/pafeed/internal/xmldoc"
 )
 
 // IsFinalResultsFile given a file name and meeting ID returns true if file
@@ -14,23 +19,214 @@ func IsFinalResultsFile(name string, meetingID int) bool {
 	return strings.HasPrefix(name, "b") && len(name) == len(fmt.Sprintf("b20140601%d2052.xml", meetingID))
 }
 
+// FeedFileInfo holds metadata encoded in a race file name.
+type FeedFileInfo struct {
+	Date       time.Time // Date of the meeting
+	MeetingID  int       // The unique identifier of the meeting
+	RaceTime   string    // Scheduled race time in hhmm format, e.g. "1927", empty if not known
+	RaceNumber int       // Race number, zero if not known
+	Revision   int       // Race message revision, zero for final results file
+}
+
+// FilenameError is returned if file name does not match race file name
+// pattern.
+type FilenameError struct {
+	Name   string // The file name
+	Reason string // Description of mismatch
+}
+
+// Error implements error interface.
+func (e *FilenameError) Error() string {
+	return fmt.Sprintf("invalid race file name %q: %s", e.Name, e.Reason)
+}
+
+// ParseFilename parses race file name to structured metadata. Race file names
+// have b<date><meetingid><race>[<revision>].xml format, e.g.
+// b2018041433736119270007.xml. Race is either race time in hhmm format or two
+// digit race number (e.g. b201804143181060038.xml of The Meadows), final
+// results file name has race time and no revision. Race and revision are taken
+// from the end of the name, digits between the date and the race are the
+// meeting ID. FilenameError is returned if name does not match the pattern.
+func ParseFilename(name string) (FeedFileInfo, error) {
+	const (
+		dateLen     = 8
+		timeLen     = 4
+		numberLen   = 2
+		revisionLen = 4
+		minMeeting  = 4 // Shortest meeting ID, used to tell race time from race number
+	)
+	var info FeedFileInfo
+	if !strings.HasPrefix(name, "b") || !strings.HasSuffix(name, ".xml") {
+		return info, &FilenameError{Name: name, Reason: "expected b<digits>.xml"}
+	}
+	digits := strings.TrimSuffix(strings.TrimPrefix(name, "b"), ".xml")
+	if !isDigits(digits) {
+		return info, &FilenameError{Name: name, Reason: "expected b<digits>.xml"}
+	}
+	if len(digits) < dateLen+1+timeLen {
+		return info, &FilenameError{Name: name, Reason: fmt.Sprintf("unexpected number of digits %d", len(digits))}
+	}
+	date, err := time.Parse("20060102", digits[:dateLen])
+	if err != nil {
+		return info, &FilenameError{Name: name, Reason: "invalid date"}
+	}
+	rest := digits[dateLen:]
+
+	// Revisions are below 100, race times are never past midnight, so the
+	// last four digits starting with "00" are a revision.
+	if suffix := rest[len(rest)-revisionLen:]; strings.HasPrefix(suffix, "00") {
+		info.Revision, _ = strconv.Atoi(suffix)
+		rest = rest[:len(rest)-revisionLen]
+		switch {
+		case len(rest) >= minMeeting+timeLen && isRaceTime(rest[len(rest)-timeLen:]):
+			info.RaceTime = rest[len(rest)-timeLen:]
+			rest = rest[:len(rest)-timeLen]
+		case len(rest) > numberLen:
+			info.RaceNumber, _ = strconv.Atoi(rest[len(rest)-numberLen:])
+			rest = rest[:len(rest)-numberLen]
+		default:
+			return FeedFileInfo{}, &FilenameError{Name: name, Reason: fmt.Sprintf("unexpected number of digits %d", len(digits))}
+		}
+	} else {
+		if !isRaceTime(suffix) {
+			return FeedFileInfo{}, &FilenameError{Name: name, Reason: "invalid race time"}
+		}
+		info.RaceTime = suffix
+		rest = rest[:len(rest)-timeLen]
+	}
+	if info.RaceNumber == 0 && info.RaceTime == "" {
+		return FeedFileInfo{}, &FilenameError{Name: name, Reason: "invalid race number"}
+	}
+	info.Date = date
+	info.MeetingID, _ = strconv.Atoi(rest)
+	return info, nil
+}
+
+// isDigits returns true if s is a non empty string of decimal digits.
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isRaceTime returns true if s is a valid race time in hhmm format.
+func isRaceTime(s string) bool {
+	if len(s) != 4 {
+		return false
+	}
+	_, err := time.Parse("1504", s)
+	return err == nil
+}
+
 // ParseFile unmarshals XML file contents to DogRacing object.
 func ParseFile(xmlBlob []byte) (*DogRacing, error) {
+	return parseReader(bytes.NewReader(xmlBlob), xmldoc.NewDecoder)
+}
+
+// ParseFileStrictUTF8 unmarshals XML file contents to DogRacing object like
+// ParseFile, except that documents not encoded in UTF-8 are rejected instead of
+// being decoded from their declared charset.
+func ParseFileStrictUTF8(xmlBlob []byte) (*DogRacing, error) {
+	return parseReader(bytes.NewReader(xmlBlob), xmldoc.NewStrictDecoder)
+}
+
+// ParseReader unmarshals a single XML document read from r to DogRacing
+// object. Unlike ParseFile the document is streamed without buffering whole
+// file contents in memory. Gzip compressed documents are decompressed
+// transparently.
+func ParseReader(r io.Reader) (*DogRacing, error) {
+	r, err := xmldoc.Decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseReader(r, xmldoc.NewDecoder)
+}
+
+// ParseReaderStrictUTF8 is like ParseReader, except that documents not encoded
+// in UTF-8 are rejected.
+func ParseReaderStrictUTF8(r io.Reader) (*DogRacing, error) {
+	r, err := xmldoc.Decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseReader(r, xmldoc.NewStrictDecoder)
+}
+
+func parseReader(r io.Reader, newDecoder func(io.Reader) *xml.Decoder) (*DogRacing, error) {
 	var obj DogRacing
-	if err := xml.Unmarshal(xmlBlob, &obj); err != nil {
+	if err := newDecoder(r).Decode(&obj); err != nil {
 		return nil, err
 	}
 	return &obj, nil
 }
 
-// ParseResult parses PA position value and returns placement position
-// and whether the dog did not finish the race.
-func ParseResult(position string) (int, bool) {
-	if position == "DN" {
-		return 0, true
+// ParseAll unmarshals every XML document of file contents holding several
+// DogRacing documents back to back. Whitespace between documents is skipped,
+// every document is decoded in the charset of its own XML declaration. Error
+// is returned if any of the documents is malformed.
+func ParseAll(xmlBlob []byte) ([]*DogRacing, error) {
+	return parseAll(xmlBlob, xmldoc.NewDecoder)
+}
+
+// ParseAllStrictUTF8 is like ParseAll, except that documents not encoded in
+// UTF-8 are rejected.
+func ParseAllStrictUTF8(xmlBlob []byte) ([]*DogRacing, error) {
+	return parseAll(xmlBlob, xmldoc.NewStrictDecoder)
+}
+
+func parseAll(xmlBlob []byte, newDecoder func(io.Reader) *xml.Decoder) ([]*DogRacing, error) {
+	var objs []*DogRacing
+	err := xmldoc.DecodeAll(xmlBlob, newDecoder, func(d *xml.Decoder) error {
+		var obj DogRacing
+		if err := d.Decode(&obj); err != nil {
+			return err
+		}
+		objs = append(objs, &obj)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	if placed, err := strconv.Atoi(position); err == nil {
-		return placed, false
+	return objs, nil
+}
+
+// MarshalFile marshals DogRacing object to XML file contents. Produced file
+// can be parsed back using ParseFile.
+func MarshalFile(obj *DogRacing) ([]byte, error) {
+	blob, err := xml.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return nil, err
 	}
-	return 0, false
+	return append([]byte(xml.Header), blob...), nil
+}
+
+// Placing is a structured representation of PA result position value.
+type Placing struct {
+	Position     int  // Finishing position, zero if the dog was not placed
+	DidNotFinish bool // Whether the dog did not finish the race
+	Disqualified bool // Whether the dog was disqualified, its placing is void
+}
+
+// ParsePlacing parses PA position value to a structured placing. Both "DSQ"
+// and "D" markers are recognised as disqualification.
+func ParsePlacing(position string) Placing {
+	switch position {
+	case "DN":
+		return Placing{DidNotFinish: true}
+	case "DSQ", "D":
+		return Placing{Disqualified: true}
+	}
+	placed, _ := strconv.Atoi(position)
+	return Placing{Position: placed}
+}
+
+// ParseResult parses PA position value and returns placement position
+// and whether the dog did not finish the race. Disqualified dog is returned as
+// not placed, use ParsePlacing to tell disqualification apart.
+func ParseResult(position string) (int, bool) {
+	p := ParsePlacing(position)
+	return p.Position, p.DidNotFinish
 }
@@ -1,9 +1,18 @@
 package greyhounds
 
 import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/advbet/pafeed/internal/charset"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIsFinalResultsFile(t *testing.T) {
@@ -32,6 +41,16 @@ func TestIsFinalResultsFile(t *testing.T) {
 			meetingID: 337361,
 			expected:  true,
 		},
+		{
+			fileName:  "b201804143181060038.xml",
+			meetingID: 3181,
+			expected:  false,
+		},
+		{
+			fileName:  "b201804143173200029.xml",
+			meetingID: 3173,
+			expected:  false,
+		},
 	}
 
 	for _, test := range tests {
@@ -71,6 +90,16 @@ func TestParseResult(t *testing.T) {
 			expectedPlace: 0,
 			expectedDNF:   false,
 		},
+		{
+			position:      "DSQ",
+			expectedPlace: 0,
+			expectedDNF:   false,
+		},
+		{
+			position:      "D",
+			expectedPlace: 0,
+			expectedDNF:   false,
+		},
 	}
 
 	for _, test := range tests {
@@ -79,3 +108,283 @@ func TestParseResult(t *testing.T) {
 		assert.Equal(t, test.expectedDNF, dnf)
 	}
 }
+
+func TestParsePlacing(t *testing.T) {
+	tests := []struct {
+		position string
+		expected Placing
+	}{
+		{
+			position: "3",
+			expected: Placing{Position: 3},
+		},
+		{
+			position: "DN",
+			expected: Placing{DidNotFinish: true},
+		},
+		{
+			position: "DSQ",
+			expected: Placing{Disqualified: true},
+		},
+		{
+			position: "D",
+			expected: Placing{Disqualified: true},
+		},
+		{
+			position: "",
+			expected: Placing{},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, ParsePlacing(test.position), test.position)
+	}
+}
+
+func TestParseReader(t *testing.T) {
+	expected := parseTestFile(t, "testdata/Crayford/b2018041433736119270028.xml")
+	for _, file := range []string{
+		"testdata/Crayford/b2018041433736119270028.xml",
+		"testdata/gzip/b2018041433736119270028.xml.gz",
+	} {
+		f, err := os.Open(file)
+		require.NoError(t, err)
+		obj, err := ParseReader(f)
+		f.Close()
+		require.NoError(t, err, file)
+		assert.Equal(t, expected, obj, file)
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	files := []string{
+		"testdata/Crayford/b2018041433736119270007.xml",
+		"testdata/Crayford/b2018041433736119270028.xml",
+		"testdata/Crayford/b201804143373611943.xml",
+	}
+	var blob []byte
+	var expected []*DogRacing
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		blob = append(blob, "\r\n\t"...)
+		blob = append(blob, b...)
+		expected = append(expected, parseTestFile(t, file))
+	}
+
+	objs, err := ParseAll(blob)
+	require.NoError(t, err)
+	assert.Equal(t, expected, objs)
+
+	objs, err = ParseAll(blob[:len(blob)-20])
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "document 3")
+	assert.Nil(t, objs)
+
+	objs, err = ParseAll(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, objs)
+}
+
+func TestParseAllCharset(t *testing.T) {
+	files := []string{
+		"testdata/Crayford/b2018041433736119270028.xml",
+		"testdata/Synthetic/windows-1252-race.xml",
+		"testdata/Crayford/b201804143373611943.xml",
+		"testdata/Synthetic/windows-1252-race.xml",
+	}
+	var blob []byte
+	var expected []*DogRacing
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		blob = append(blob, b...)
+		expected = append(expected, parseTestFile(t, file))
+	}
+
+	objs, err := ParseAll(blob)
+	require.NoError(t, err)
+	require.Len(t, objs, 4)
+	assert.Equal(t, "Clonmannon Señora", objs[1].Meetings[0].Races[0].Traps[0].Dog.Name)
+	assert.Equal(t, "Clonmannon Señora", objs[3].Meetings[0].Races[0].Traps[0].Dog.Name)
+	assert.Equal(t, expected, objs)
+}
+
+func TestParseFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		info FeedFileInfo
+		err  bool
+	}{
+		{
+			name: "b2018041433736119270007.xml",
+			info: FeedFileInfo{
+				Date:      time.Date(2018, 4, 14, 0, 0, 0, 0, time.UTC),
+				MeetingID: 337361,
+				RaceTime:  "1927",
+				Revision:  7,
+			},
+		},
+		{
+			name: "b201804143373611927.xml",
+			info: FeedFileInfo{
+				Date:      time.Date(2018, 4, 14, 0, 0, 0, 0, time.UTC),
+				MeetingID: 337361,
+				RaceTime:  "1927",
+			},
+		},
+		{
+			name: "b201804143181060038.xml",
+			info: FeedFileInfo{
+				Date:       time.Date(2018, 4, 14, 0, 0, 0, 0, time.UTC),
+				MeetingID:  3181,
+				RaceNumber: 6,
+				Revision:   38,
+			},
+		},
+		{
+			name: "b201804143173200029.xml",
+			info: FeedFileInfo{
+				Date:       time.Date(2018, 4, 14, 0, 0, 0, 0, time.UTC),
+				MeetingID:  3173,
+				RaceNumber: 20,
+				Revision:   29,
+			},
+		},
+		{
+			name: "b201902272142030005.xml",
+			info: FeedFileInfo{
+				Date:       time.Date(2019, 2, 27, 0, 0, 0, 0, time.UTC),
+				MeetingID:  2142,
+				RaceNumber: 3,
+				Revision:   5,
+			},
+		},
+		{name: "c20180414cra5_337361.xml", err: true},
+		{name: "b20180414337361192.xml", err: true},
+		{name: "b201813143373611927.xml", err: true},
+		{name: "b201804143373612575.xml", err: true},
+		{name: "b2018041433736119270007.txt", err: true},
+	}
+
+	for _, test := range tests {
+		info, err := ParseFilename(test.name)
+		if test.err {
+			assert.Error(t, err, test.name)
+			assert.IsType(t, &FilenameError{}, err, test.name)
+			continue
+		}
+		require.NoError(t, err, test.name)
+		assert.Equal(t, test.info, info, test.name)
+	}
+}
+
+func TestParseFilenameFixtures(t *testing.T) {
+	dirs := []string{
+		"testdata/Crayford",
+		"testdata/Nottingham",
+		"testdata/Perry Barr",
+		"testdata/The Meadows",
+		"testdata/Wheeling Island",
+		"testdata/feed",
+	}
+
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		require.NoError(t, err, dir)
+		for _, f := range files {
+			if !strings.HasPrefix(f.Name(), "b") {
+				continue
+			}
+			info, err := ParseFilename(f.Name())
+			require.NoError(t, err, f.Name())
+			obj := parseTestFile(t, path.Join(dir, f.Name()))
+			require.Len(t, obj.Meetings, 1, f.Name())
+			require.Len(t, obj.Meetings[0].Races, 1, f.Name())
+			race := obj.Meetings[0].Races[0]
+			assert.Equal(t, obj.Meetings[0].MeetingID, info.MeetingID, f.Name())
+			if info.RaceNumber != 0 {
+				assert.Equal(t, race.RaceNumber, info.RaceNumber, f.Name())
+			}
+			if info.Revision != 0 {
+				assert.Equal(t, race.Revision, info.Revision, f.Name())
+			}
+		}
+	}
+}
+
+func TestParseFileCharset(t *testing.T) {
+	file := "testdata/Synthetic/windows-1252-race.xml"
+	obj := parseTestFile(t, file)
+	require.Len(t, obj.Meetings, 1)
+	require.Len(t, obj.Meetings[0].Races, 1)
+	require.NotEmpty(t, obj.Meetings[0].Races[0].Traps)
+	require.NotNil(t, obj.Meetings[0].Races[0].Traps[0].Dog)
+	assert.Equal(t, "Clonmannon Señora", obj.Meetings[0].Races[0].Traps[0].Dog.Name)
+
+	blob, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	_, err = ParseFileStrictUTF8(blob)
+	assert.Error(t, err)
+
+	blob, err = ioutil.ReadFile("testdata/Crayford/b2018041433736119270007.xml")
+	require.NoError(t, err)
+	strict, err := ParseFileStrictUTF8(blob)
+	require.NoError(t, err)
+	assert.Equal(t, parseTestFile(t, "testdata/Crayford/b2018041433736119270007.xml"), strict)
+}
+
+func TestParseStrictUTF8(t *testing.T) {
+	blob, err := ioutil.ReadFile("testdata/Synthetic/windows-1252-race.xml")
+	require.NoError(t, err)
+	r, err := charset.NewReader("windows-1252", bytes.NewReader(blob))
+	require.NoError(t, err)
+	utf8, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	utf8 = bytes.Replace(utf8, []byte("windows-1252"), []byte("UTF-8"), 1)
+
+	tests := []struct {
+		name   string
+		parse  func([]byte) error
+		strict func([]byte) error
+	}{
+		{
+			name:   "ParseFile",
+			parse:  func(b []byte) error { _, err := ParseFile(b); return err },
+			strict: func(b []byte) error { _, err := ParseFileStrictUTF8(b); return err },
+		},
+		{
+			name:   "ParseReader",
+			parse:  func(b []byte) error { _, err := ParseReader(bytes.NewReader(b)); return err },
+			strict: func(b []byte) error { _, err := ParseReaderStrictUTF8(bytes.NewReader(b)); return err },
+		},
+		{
+			name:   "ParseAll",
+			parse:  func(b []byte) error { _, err := ParseAll(b); return err },
+			strict: func(b []byte) error { _, err := ParseAllStrictUTF8(b); return err },
+		},
+	}
+
+	for _, test := range tests {
+		assert.NoError(t, test.parse(blob), test.name)
+		assert.Error(t, test.strict(blob), test.name)
+		assert.NoError(t, test.strict(utf8), test.name)
+	}
+}
+
+func TestParseFileErrorContext(t *testing.T) {
+	blob, err := ioutil.ReadFile("testdata/Crayford/b201804143373611927.xml")
+	require.NoError(t, err)
+	blob = bytes.Replace(blob, []byte(`runTime="24.38"`), []byte(`runTime="24.3x"`), 1)
+
+	_, err = ParseFile(blob)
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "meeting 337361: race 1: trap 4: "), err.Error())
+
+	// wrapped errors are kept intact
+	obj, err := ParseFile([]byte(`<DogRacing type="Race"><Meeting meetingId="1"><Race raceNumber="2" type="Flat" winTime="x"/></Meeting></DogRacing>`))
+	assert.Nil(t, obj)
+	inner := errors.Unwrap(errors.Unwrap(err))
+	require.NotNil(t, inner)
+	assert.Equal(t, "meeting 1: race 2: "+inner.Error(), err.Error())
+}
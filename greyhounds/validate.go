@@ -0,0 +1,86 @@
+package greyhounds
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError lists all the problems found while validating a document.
+type ValidationError []error
+
+// Error implements error interface.
+func (e ValidationError) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d validation error(s): %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Validate checks document for structural consistency: message type and
+// meeting states must be valid, forecast and tricast dividends must reference
+// existing traps and every show must have a well formed price unless no offers
+// are made, in which case show must have no price. ValidationError listing all
+// the problems is returned if document is inconsistent.
+func (r *DogRacing) Validate() error {
+	var errs ValidationError
+	if !r.Type.isValid() {
+		errs = append(errs, fmt.Errorf("invalid message type: %q", r.Type))
+	}
+	for _, m := range r.Meetings {
+		if !m.State.isValid() {
+			errs = append(errs, fmt.Errorf("meeting %d: invalid state: %q", m.MeetingID, m.State))
+		}
+		for _, race := range m.Races {
+			for _, err := range race.validate() {
+				errs = append(errs, fmt.Errorf("meeting %d: race %d: %s", m.MeetingID, race.RaceNumber, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validate returns a list of problems found in a race.
+func (r *Race) validate() []error {
+	var errs []error
+	traps := make(map[int]bool, len(r.Traps))
+	for _, t := range r.Traps {
+		traps[t.TrapNo] = true
+		for _, s := range t.Shows {
+			if s.NoOffers {
+				if s.Price != nil {
+					errs = append(errs, fmt.Errorf("trap %d: show at %s has price but no offers", t.TrapNo, s.TimeStamp))
+				}
+				continue
+			}
+			if s.Price == nil {
+				errs = append(errs, fmt.Errorf("trap %d: show at %s has no price", t.TrapNo, s.TimeStamp))
+				continue
+			}
+			if err := s.Price.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("trap %d: show at %s: %s", t.TrapNo, s.TimeStamp, err))
+			}
+		}
+	}
+	if r.Dividends == nil {
+		return errs
+	}
+	for _, f := range r.Dividends.Forecast {
+		for _, trap := range []int{f.Trap1, f.Trap2} {
+			if !traps[trap] {
+				errs = append(errs, fmt.Errorf("forecast %d-%d references unknown trap %d", f.Trap1, f.Trap2, trap))
+			}
+		}
+	}
+	for _, tc := range r.Dividends.Tricast {
+		for _, trap := range []int{tc.Trap1, tc.Trap2, tc.Trap3} {
+			if !traps[trap] {
+				errs = append(errs, fmt.Errorf("tricast %d-%d-%d references unknown trap %d", tc.Trap1, tc.Trap2, tc.Trap3, trap))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,73 @@
+package greyhounds
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFeed(t *testing.T) {
+	dirs := []string{
+		"testdata/Crayford",
+		"testdata/Nottingham",
+		"testdata/Perry Barr",
+		"testdata/The Meadows",
+		"testdata/Wheeling Island",
+		"testdata/feed",
+	}
+
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		require.NoError(t, err, dir)
+		for _, f := range files {
+			path := path.Join(dir, f.Name())
+			assert.NoError(t, parseTestFile(t, path).Validate(), path)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	obj := DogRacing{
+		Type: "Unknown",
+		Meetings: []Meeting{
+			{
+				MeetingID: 1,
+				State:     "Unknown",
+				Races: []Race{
+					{
+						RaceNumber: 1,
+						Traps: []Trap{
+							{
+								TrapNo: 1,
+								Shows: []Show{
+									{NoOffers: true},
+									{NoOffers: false},
+									{NoOffers: true, Price: &Price{}},
+								},
+							},
+							{TrapNo: 2},
+						},
+						Dividends: &Dividends{
+							Forecast: []Forecast{{Trap1: 1, Trap2: 2}, {Trap1: 1, Trap2: 7}},
+							Tricast:  []Tricast{{Trap1: 8, Trap2: 1, Trap3: 9}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := obj.Validate()
+	require.Error(t, err)
+	errs, ok := err.(ValidationError)
+	require.True(t, ok)
+	assert.Len(t, errs, 7)
+
+	invalid := parseTestFile(t, "testdata/Invalid/b2018041433736119270007.xml")
+	err = invalid.Validate()
+	require.Error(t, err)
+	assert.Len(t, err.(ValidationError), 1)
+}
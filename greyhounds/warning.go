@@ -0,0 +1,227 @@
+package greyhounds
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/advbet/pafeed/internal/xmldoc"
+)
+
+// Warning is a recoverable problem found while parsing a document in lenient
+// mode. Offending value is kept in the parsed object as is.
+type Warning struct {
+	Path  string // Location of the element, e.g. "meeting 337361 race 1 trap 4"
+	Attr  string // Name of the offending attribute, e.g. "seeding"
+	Value string // Offending value
+	Err   error  // Description of the problem
+}
+
+// String returns human readable warning description.
+func (w Warning) String() string {
+	if w.Path == "" {
+		return w.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", w.Path, w.Err)
+}
+
+// ParseFileWithWarnings unmarshals XML file contents to DogRacing object.
+// Unlike ParseFile recoverable problems do not fail the whole document, they
+// are reported as warnings in document order instead:
+//
+//   - unknown enum values (e.g. new race state introduced by PA) are kept as is;
+//   - unparseable durations are left zero;
+//   - out of range trap numbers are kept as is;
+//   - shows having price and no offers at the same time (or neither) are kept
+//     as is.
+func ParseFileWithWarnings(xmlBlob []byte) (*DogRacing, []Warning, error) {
+	var obj xmlDogRacing
+	c := &warningChecker{r: xmldoc.NewDecoder(bytes.NewReader(xmlBlob))}
+	if err := xml.NewTokenDecoder(c).Decode(&obj); err != nil {
+		return nil, nil, err
+	}
+	return (*DogRacing)(&obj), c.warnings, nil
+}
+
+// maxTrapNo is the highest trap number used by greyhound tracks.
+const maxTrapNo = 8
+
+// warningChecker is a token reader of a document parsed in lenient mode. It
+// records recoverable problems found in element attributes as warnings and
+// replaces unparseable durations with empty values, so that XML unmarshalers
+// leave them zero instead of failing the document.
+type warningChecker struct {
+	r        xml.TokenReader
+	path     []string  // Location labels of open elements, empty for elements not having one
+	show     *showInfo // Show element being read, nil outside of shows
+	warnings []Warning
+}
+
+// showInfo holds state of a Show element needed to check its price.
+type showInfo struct {
+	noOffers bool
+	value    string // Raw noOffers attribute value
+	hasPrice bool
+}
+
+// Token implements xml.TokenReader interface.
+func (c *warningChecker) Token() (xml.Token, error) {
+	tok, err := c.r.Token()
+	if err != nil {
+		return tok, err
+	}
+	switch t := tok.(type) {
+	case xml.StartElement:
+		c.path = append(c.path, pathLabel(t))
+		return c.checkElement(t), nil
+	case xml.EndElement:
+		if t.Name.Local == "Show" && c.show != nil {
+			c.checkShowPrice()
+			c.show = nil
+		}
+		if len(c.path) > 0 {
+			c.path = c.path[:len(c.path)-1]
+		}
+	}
+	return tok, nil
+}
+
+// pathLabel returns location label of element, e.g. "race 1", empty label is
+// returned for elements not identifying the location.
+func pathLabel(e xml.StartElement) string {
+	var attr string
+	switch e.Name.Local {
+	case "Meeting":
+		attr = "meetingId"
+	case "Race":
+		attr = "raceNumber"
+	case "Trap":
+		attr = "trap"
+	case "Dog":
+		attr = "id"
+	default:
+		return ""
+	}
+	return fmt.Sprintf("%s %s", strings.ToLower(e.Name.Local), attrValue(e, attr, ""))
+}
+
+// checkElement records warnings for attributes of element e. Returned element
+// has unparseable durations replaced by empty values.
+func (c *warningChecker) checkElement(e xml.StartElement) xml.StartElement {
+	switch e.Name.Local {
+	case "DogRacing":
+		c.checkEnum(e, "Message", "type", "", func(v string) bool { return MessageType(v).isValid() })
+	case "Meeting":
+		c.checkEnum(e, "Meeting", "state", string(MeetingDormant), func(v string) bool { return MeetingState(v).isValid() })
+	case "Race":
+		c.checkEnum(e, "Race", "type", "", func(v string) bool { return RaceType(v).isValid() })
+		c.checkEnum(e, "Race", "state", string(RaceDormant), func(v string) bool { return RaceState(v).isValid() })
+		e = c.checkDurations(e, "winTime")
+	case "Trap", "FormTrap":
+		c.checkTrapNo(e)
+		c.checkEnum(e, "Trap", "seeding", "", func(v string) bool { return TrapSeeding(v).isValid() })
+	case "BestTime":
+		e = c.checkDurations(e, "adjustedTime")
+	case "FormRace":
+		e = c.checkDurations(e, "winningTime")
+	case "Result":
+		e = c.checkDurations(e, "sectionalTime", "runTime", "adjustedTime")
+	case "Show":
+		c.show = newShowInfo(e)
+	case "Price":
+		if c.show != nil {
+			c.show.hasPrice = true
+		}
+	}
+	return e
+}
+
+// warn records a warning located at the current element.
+func (c *warningChecker) warn(attr, value string, err error) {
+	var labels []string
+	for _, l := range c.path {
+		if l != "" {
+			labels = append(labels, l)
+		}
+	}
+	c.warnings = append(c.warnings, Warning{
+		Path:  strings.Join(labels, " "),
+		Attr:  attr,
+		Value: value,
+		Err:   err,
+	})
+}
+
+// checkEnum records a warning if attribute attr of element e is not a valid
+// enum value. Absent attribute is checked using default value def.
+func (c *warningChecker) checkEnum(e xml.StartElement, element, attr, def string, valid func(string) bool) {
+	if value := attrValue(e, attr, def); !valid(value) {
+		c.warn(attr, value, fmt.Errorf("invalid %s %s attibute value: %s", element, attr, value))
+	}
+}
+
+// checkTrapNo records a warning if trap number is out of range. Strict parsing
+// accepts any trap number.
+func (c *warningChecker) checkTrapNo(e xml.StartElement) {
+	value := attrValue(e, "trap", "")
+	trapNo, err := strconv.Atoi(value)
+	if err != nil {
+		return // reported by the unmarshaler
+	}
+	if trapNo < 1 || trapNo > maxTrapNo {
+		c.warn("trap", value, fmt.Errorf("trap number %d out of range 1-%d", trapNo, maxTrapNo))
+	}
+}
+
+// newShowInfo returns state of Show element e. The noOffers attribute is parsed
+// the same way as by xmlYesNo, absent attribute means offers are present.
+func newShowInfo(e xml.StartElement) *showInfo {
+	value := attrValue(e, "noOffers", "No")
+	var noOffers xmlYesNo
+	// invalid values are reported by the unmarshaler
+	_ = noOffers.UnmarshalXMLAttr(xml.Attr{Name: xml.Name{Local: "noOffers"}, Value: value})
+	return &showInfo{noOffers: bool(noOffers), value: value}
+}
+
+// checkShowPrice records a warning if show price presence contradicts noOffers
+// attribute. Strict parsing accepts such shows, see Validate.
+func (c *warningChecker) checkShowPrice() {
+	switch {
+	case c.show.noOffers && c.show.hasPrice:
+		c.warn("noOffers", c.show.value, errors.New("show has price but no offers"))
+	case !c.show.noOffers && !c.show.hasPrice:
+		c.warn("noOffers", c.show.value, errors.New("show has no price"))
+	}
+}
+
+// checkDurations records a warning for every duration attribute of element e
+// that can not be parsed. Returned element has such attributes emptied.
+func (c *warningChecker) checkDurations(e xml.StartElement, attrs ...string) xml.StartElement {
+	for _, name := range attrs {
+		for i, a := range e.Attr {
+			if a.Name.Local != name {
+				continue
+			}
+			if _, err := parseDuration(a.Value); err != nil {
+				c.warn(name, a.Value, err)
+				e = e.Copy()
+				e.Attr[i].Value = ""
+			}
+		}
+	}
+	return e
+}
+
+// attrValue returns value of element e attribute name, def is returned if
+// element has no such attribute.
+func attrValue(e xml.StartElement, name, def string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return def
+}
@@ -0,0 +1,88 @@
+package greyhounds
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFileWithWarnings(t *testing.T) {
+	blob, err := ioutil.ReadFile("testdata/Invalid/b201804143373611927.xml")
+	require.NoError(t, err)
+
+	_, err = ParseFile(blob)
+	assert.EqualError(t, err, `meeting 337361: race 1: trap 4: strconv.ParseInt: parsing "243x": invalid syntax`)
+
+	fixed := bytes.Replace(blob, []byte(`runTime="24.3x"`), []byte(`runTime="24.30"`), 1)
+	_, err = ParseFile(fixed)
+	assert.EqualError(t, err, `meeting 337361: race 1: invalid Race state attibute value: Hare Stopped`)
+
+	dr, warnings, err := ParseFileWithWarnings(blob)
+	require.NoError(t, err)
+	expected := []struct {
+		path  string
+		attr  string
+		value string
+	}{
+		{path: "meeting 337361 race 1", attr: "state", value: "Hare Stopped"},
+		{path: "meeting 337361 race 1 trap 1", attr: "noOffers", value: "Yes"},
+		{path: "meeting 337361 race 1 trap 2", attr: "seeding", value: "Centre"},
+		{path: "meeting 337361 race 1 trap 4", attr: "runTime", value: "24.3x"},
+		{path: "meeting 337361 race 1 trap 9", attr: "trap", value: "9"},
+	}
+	require.Len(t, warnings, len(expected))
+	for i, e := range expected {
+		assert.Equal(t, e.path, warnings[i].Path)
+		assert.Equal(t, e.attr, warnings[i].Attr)
+		assert.Equal(t, e.value, warnings[i].Value)
+	}
+	assert.Equal(t, "meeting 337361 race 1: invalid Race state attibute value: Hare Stopped", warnings[0].String())
+
+	race := dr.Meetings[0].Races[0]
+	assert.Equal(t, RaceState("Hare Stopped"), race.State)
+	assert.Equal(t, TrapSeeding("Centre"), race.Traps[1].Seeding)
+	assert.Zero(t, race.Traps[3].Result.RunTime)
+	assert.Equal(t, 9, race.Traps[5].TrapNo)
+
+	// valid documents produce no warnings
+	blob, err = ioutil.ReadFile("testdata/Crayford/b201804143373611927.xml")
+	require.NoError(t, err)
+	_, warnings, err = ParseFileWithWarnings(blob)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestParseFileWithWarningsShowPrice(t *testing.T) {
+	tests := []struct {
+		show     string
+		expected []string
+	}{
+		{show: `<Show timeStamp="20180414T182000+0000" noOffers="yes"/>`},
+		{show: `<Show timeStamp="20180414T182000+0000" noOffers="Yes"/>`},
+		{show: `<Show timeStamp="20180414T182000+0000" noOffers="no"><Price numerator="4" denominator="1"/></Show>`},
+		{show: `<Show timeStamp="20180414T182000+0000"><Price numerator="4" denominator="1"/></Show>`},
+		{
+			show:     `<Show timeStamp="20180414T182000+0000" noOffers="yes"><Price numerator="4" denominator="1"/></Show>`,
+			expected: []string{"meeting 1 race 1 trap 1: show has price but no offers"},
+		},
+		{
+			show:     `<Show timeStamp="20180414T182000+0000" noOffers="no"/>`,
+			expected: []string{"meeting 1 race 1 trap 1: show has no price"},
+		},
+	}
+
+	for _, test := range tests {
+		blob := []byte(`<DogRacing type="Race"><Meeting meetingId="1"><Race raceNumber="1" type="Flat"><Trap trap="1">` +
+			test.show + `</Trap></Race></Meeting></DogRacing>`)
+		_, warnings, err := ParseFileWithWarnings(blob)
+		require.NoError(t, err, test.show)
+		var messages []string
+		for _, w := range warnings {
+			messages = append(messages, w.String())
+		}
+		assert.Equal(t, test.expected, messages, test.show)
+	}
+}
@@ -0,0 +1,148 @@
+package horses
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Eligibility is the structured form of race eligibility, e.g. "4YO plus".
+type Eligibility struct {
+	MinAge    int // Minimum age of horses in years, zero if not known
+	MaxAge    int // Maximum age of horses in years, zero if there is no upper limit
+	MinRating int // Minimum official rating of horses, zero if not restricted
+	MaxRating int // Maximum official rating of horses, zero if not restricted
+}
+
+// Eligibility text patterns, matched against lower case text.
+var (
+	ageRangeRe = regexp.MustCompile(`(\d+)\s*(?:yo)?\s*(?:to|-)\s*(\d+)\s*yo`)
+	agePlusRe  = regexp.MustCompile(`(\d+)\s*yo\s*(?:\+|plus|and up)`)
+	ageRe      = regexp.MustCompile(`(\d+)\s*yo`)
+	ratingRe   = regexp.MustCompile(`(\d+)\s*-\s*(\d+)`)
+)
+
+// SilksKey returns a key identifying jockey colours (silks) image. It is the
+// colours graphics file name when present, otherwise a hash of the textual
+// colours description is returned. Empty string is returned if neither file
+// nor description is known.
+func (h *CardHorse) SilksKey() string {
+	if h.JockeyColoursFile != "" {
+		return h.JockeyColoursFile
+	}
+	if h.JockeyColours == "" {
+		return ""
+	}
+	sum := sha1.Sum([]byte(h.JockeyColours))
+	return hex.EncodeToString(sum[:])
+}
+
+// colourCodes maps horse colour abbreviations to colour names.
+var colourCodes = map[string]string{
+	"b":  "bay",
+	"bl": "black",
+	"br": "brown",
+	"ch": "chestnut",
+	"gr": "grey",
+	"ro": "roan",
+	"wh": "white",
+}
+
+// ColourNames returns the colours of the horse spelled out in full, e.g. "ch"
+// is returned as "chestnut". Combined colours keep their separator, "b/br" is
+// returned as "bay/brown". Unknown codes are returned as is.
+func (h *CardHorse) ColourNames() []string {
+	names := make([]string, 0, len(h.Colours))
+	for _, colour := range h.Colours {
+		parts := strings.Split(colour, "/")
+		for i, part := range parts {
+			if name, ok := colourCodes[strings.ToLower(strings.TrimSpace(part))]; ok {
+				parts[i] = name
+			}
+		}
+		names = append(names, strings.Join(parts, "/"))
+	}
+	return names
+}
+
+// IsOversubscribed returns true if more horses are declared to run than the
+// maximum field size allows. Such races are usually balloted. Non runners are
+// not counted as declared horses. False is returned if the maximum field size
+// is unknown.
+func (r *CardRace) IsOversubscribed() bool {
+	if r.MaxRunners == 0 {
+		return false
+	}
+	declared := 0
+	for _, h := range r.Horses {
+		if h.Status != CardHorseNonRunner {
+			declared++
+		}
+	}
+	return declared > r.MaxRunners
+}
+
+// IsTwoYearOldRace returns true if race is restricted to two-year-old horses.
+// Race eligibility is used if known, otherwise all declared horses must be two
+// years old.
+func (r *CardRace) IsTwoYearOldRace() bool {
+	return r.isAgeOnlyRace(2)
+}
+
+// IsJuvenileHurdle returns true if race is a National Hunt hurdle for horses in
+// their first jumps season, that is three-year-olds, or four-year-olds in the
+// second half of the season. Race is juvenile if it is titled so or restricted
+// to a single one of these ages.
+func (r *CardRace) IsJuvenileHurdle() bool {
+	if r.RaceType != RaceHurdle {
+		return false
+	}
+	if strings.Contains(strings.ToLower(r.Title), "juvenile") {
+		return true
+	}
+	return r.isAgeOnlyRace(3) || r.isAgeOnlyRace(4)
+}
+
+// EligibilityDetail parses race eligibility, e.g. "3YO only", "4YO plus",
+// "4YO to 6YO" or "4yo+ 0-105" where the last part is the rating band of a
+// handicap. Unknown parts are left zero, the raw text is kept in Eligibility.
+func (r *CardRace) EligibilityDetail() Eligibility {
+	var e Eligibility
+	s := strings.ToLower(r.Eligibility)
+	if m := ageRangeRe.FindStringSubmatch(s); m != nil {
+		e.MinAge, _ = strconv.Atoi(m[1])
+		e.MaxAge, _ = strconv.Atoi(m[2])
+		s = strings.Replace(s, m[0], "", 1)
+	} else if m := agePlusRe.FindStringSubmatch(s); m != nil {
+		e.MinAge, _ = strconv.Atoi(m[1])
+		s = strings.Replace(s, m[0], "", 1)
+	} else if m := ageRe.FindStringSubmatch(s); m != nil {
+		e.MinAge, _ = strconv.Atoi(m[1])
+		e.MaxAge = e.MinAge
+		s = strings.Replace(s, m[0], "", 1)
+	}
+	if m := ratingRe.FindStringSubmatch(s); m != nil {
+		e.MinRating, _ = strconv.Atoi(m[1])
+		e.MaxRating, _ = strconv.Atoi(m[2])
+	}
+	return e
+}
+
+// isAgeOnlyRace returns true if race is restricted to horses of a given age.
+func (r *CardRace) isAgeOnlyRace(age int) bool {
+	if r.Eligibility != "" {
+		e := r.EligibilityDetail()
+		return e.MinAge == age && e.MaxAge == age
+	}
+	if len(r.Horses) == 0 {
+		return false
+	}
+	for _, h := range r.Horses {
+		if h.AgeInYears != age {
+			return false
+		}
+	}
+	return true
+}
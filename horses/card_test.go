@@ -0,0 +1,211 @@
+package horses
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseTestCardFile(t *testing.T, file string) *RacingCardFile {
+	blob, err := ioutil.ReadFile(file)
+	require.NoError(t, err, file)
+	obj, err := ParseRacingCardFile(blob)
+	require.NoError(t, err, file)
+	return obj
+}
+
+func TestCardHorseSilksKey(t *testing.T) {
+	tests := []struct {
+		horse CardHorse
+		key   string
+	}{
+		{
+			horse: CardHorse{
+				JockeyColours:     "Royal blue and white check, white sleeves, royal blue diamonds",
+				JockeyColoursFile: "20180414lin135501.png",
+			},
+			key: "20180414lin135501.png",
+		},
+		{
+			horse: CardHorse{
+				JockeyColours: "Royal blue and white check, white sleeves, royal blue diamonds",
+			},
+			key: "7e7026ce257676cdef72878e09f90c324d2daee3",
+		},
+		{
+			horse: CardHorse{},
+			key:   "",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.key, test.horse.SilksKey())
+	}
+}
+
+func TestCardHorseColourNames(t *testing.T) {
+	tests := []struct {
+		colours []string
+		names   []string
+	}{
+		{colours: []string{"b"}, names: []string{"bay"}},
+		{colours: []string{"ch"}, names: []string{"chestnut"}},
+		{colours: []string{"gr"}, names: []string{"grey"}},
+		{colours: []string{"br"}, names: []string{"brown"}},
+		{colours: []string{"bl"}, names: []string{"black"}},
+		{colours: []string{"CH"}, names: []string{"chestnut"}},
+		{colours: []string{"B/BR"}, names: []string{"bay/brown"}},
+		{colours: []string{"gr", "ro"}, names: []string{"grey", "roan"}},
+		{colours: []string{"xx"}, names: []string{"xx"}},
+		{colours: nil, names: []string{}},
+	}
+
+	for _, test := range tests {
+		h := CardHorse{Colours: test.colours}
+		assert.Equal(t, test.names, h.ColourNames(), test.colours)
+	}
+}
+
+func TestCardRaceIsOversubscribed(t *testing.T) {
+	tests := []struct {
+		xml      string
+		expected bool
+	}{
+		{
+			xml: `<Race id="1" date="20180414" time="1355+0100" maxRunners="2">
+			  <Horse id="1"/><Horse id="2"/><Horse id="3"/>
+			</Race>`,
+			expected: true,
+		},
+		{
+			xml: `<Race id="1" date="20180414" time="1355+0100" maxRunners="2">
+			  <Horse id="1"/><Horse id="2"/><Horse id="3" status="NonRunner"/>
+			</Race>`,
+			expected: false,
+		},
+		{
+			xml: `<Race id="1" date="20180414" time="1355+0100">
+			  <Horse id="1"/><Horse id="2"/><Horse id="3"/>
+			</Race>`,
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		var r xmlCardRace
+		require.NoError(t, xml.Unmarshal([]byte(test.xml), &r))
+		race := CardRace(r)
+		assert.Equal(t, test.expected, race.IsOversubscribed(), test.xml)
+	}
+
+	cards := parseTestCardFile(t, "testdata/Lingfield/c20180414lin.xml")
+	for _, race := range (*cards)[0].Races {
+		assert.False(t, race.IsOversubscribed(), race.ID)
+	}
+
+	// Rosehill card declares non runners, they are not counted
+	cards = parseTestCardFile(t, "testdata/feed/c20190227rsh.xml")
+	nonRunners := 0
+	for _, race := range (*cards)[0].Races {
+		for _, h := range race.Horses {
+			if h.Status == CardHorseNonRunner {
+				nonRunners++
+			}
+		}
+		assert.False(t, race.IsOversubscribed(), race.ID)
+	}
+	assert.Equal(t, 3, nonRunners)
+
+	cards = parseTestCardFile(t, "testdata/Synthetic/oversubscribed-card.xml")
+	races := (*cards)[0].Races
+	require.Len(t, races, 2)
+	assert.True(t, races[0].IsOversubscribed())
+	assert.False(t, races[1].IsOversubscribed())
+}
+
+func TestCardRaceAgeGroups(t *testing.T) {
+	tests := []struct {
+		race     CardRace
+		twoYO    bool
+		juvenile bool
+	}{
+		{
+			race:  CardRace{RaceType: RaceFlat, Eligibility: "2YO only"},
+			twoYO: true,
+		},
+		{
+			// Eligibility is unknown, ages of horses are used
+			race:  CardRace{RaceType: RaceFlat, Horses: []CardHorse{{AgeInYears: 2}, {AgeInYears: 2}}},
+			twoYO: true,
+		},
+		{
+			race: CardRace{RaceType: RaceFlat, Horses: []CardHorse{{AgeInYears: 2}, {AgeInYears: 3}}},
+		},
+		{
+			race: CardRace{RaceType: RaceFlat},
+		},
+		{
+			race:     CardRace{RaceType: RaceHurdle, Eligibility: "3YO only"},
+			juvenile: true,
+		},
+		{
+			race:     CardRace{RaceType: RaceHurdle, Eligibility: "4YO only"},
+			juvenile: true,
+		},
+		{
+			race:     CardRace{RaceType: RaceHurdle, Eligibility: "3YO plus", Title: "Bet365 Juvenile Hurdle"},
+			juvenile: true,
+		},
+		{
+			race: CardRace{RaceType: RaceFlat, Eligibility: "3YO only"},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.twoYO, test.race.IsTwoYearOldRace(), test.race.Eligibility)
+		assert.Equal(t, test.juvenile, test.race.IsJuvenileHurdle(), test.race.Eligibility)
+	}
+
+	// Two-year-old flat race
+	cards := parseTestCardFile(t, "testdata/NewcastleRule4AllBets/c20180419ncs.xml")
+	for _, race := range (*cards)[0].Races {
+		assert.Equal(t, race.ID == 799493, race.IsTwoYearOldRace(), race.ID)
+		assert.False(t, race.IsJuvenileHurdle(), race.ID)
+	}
+
+	// Hurdles open to older horses
+	cards = parseTestCardFile(t, "testdata/Aintree/c20180414ain.xml")
+	for _, race := range (*cards)[0].Races {
+		assert.False(t, race.IsTwoYearOldRace(), race.ID)
+		assert.False(t, race.IsJuvenileHurdle(), race.ID)
+	}
+}
+
+func TestCardRaceEligibilityDetail(t *testing.T) {
+	tests := []struct {
+		eligibility string
+		detail      Eligibility
+	}{
+		{eligibility: "2YO only", detail: Eligibility{MinAge: 2, MaxAge: 2}},
+		{eligibility: "3YO plus", detail: Eligibility{MinAge: 3}},
+		{eligibility: "4YO to 6YO", detail: Eligibility{MinAge: 4, MaxAge: 6}},
+		{eligibility: "4yo+ 0-105", detail: Eligibility{MinAge: 4, MinRating: 0, MaxRating: 105}},
+		{eligibility: "3yo 46-65", detail: Eligibility{MinAge: 3, MaxAge: 3, MinRating: 46, MaxRating: 65}},
+		{eligibility: "2-3yo", detail: Eligibility{MinAge: 2, MaxAge: 3}},
+		{eligibility: "", detail: Eligibility{}},
+		{eligibility: "Amateur riders", detail: Eligibility{}},
+	}
+
+	for _, test := range tests {
+		race := CardRace{Eligibility: test.eligibility}
+		assert.Equal(t, test.detail, race.EligibilityDetail(), test.eligibility)
+	}
+
+	cards := parseTestCardFile(t, "testdata/NewcastleRule4AllBets/c20180419ncs.xml")
+	for _, race := range (*cards)[0].Races {
+		assert.NotZero(t, race.EligibilityDetail().MinAge, race.Eligibility)
+	}
+}
@@ -0,0 +1,200 @@
+package horses
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Change is a single field level change between two states of a race.
+type Change struct {
+	Path string      // Changed field, e.g. "status" or "horse 1547854 result"
+	Old  interface{} // Value in the old race state, nil if absent
+	New  interface{} // Value in the new race state, nil if absent
+}
+
+// DiffRace returns changes between two states of the same race. Reported
+// changes are:
+//
+//   - "status", "stewards", "off time" and "win time" of the race;
+//   - "horse <id> status" of every horse, old value is nil for added horses;
+//   - "horse <id> show" for every show not present in the old race, old value
+//     is always nil;
+//   - "horse <id> starting price" as fractional strings;
+//   - "horse <id> result" and "horse <id> casualty" of every horse;
+//   - "returns" when returns are posted or removed, changes of posted returns
+//     are not reported.
+//
+// Values are of the field types, optional fields are dereferenced. Horses are
+// reported in the order of the new race, changes of removed horses are not
+// reported.
+func DiffRace(old, new Race) []Change {
+	var changes []Change
+	add := func(path string, o, n interface{}) {
+		changes = append(changes, Change{Path: path, Old: o, New: n})
+	}
+
+	if old.Status != new.Status {
+		add("status", old.Status, new.Status)
+	}
+	if old.Stewards != new.Stewards {
+		add("stewards", old.Stewards, new.Stewards)
+	}
+	if !equalTimes(old.OffTime, new.OffTime) {
+		add("off time", timeValue(old.OffTime), timeValue(new.OffTime))
+	}
+	if o, n := durationValue(old.WinTime), durationValue(new.WinTime); o != n {
+		add("win time", o, n)
+	}
+
+	for i := range new.Horses {
+		h := &new.Horses[i]
+		prefix := fmt.Sprintf("horse %d", h.ID)
+		o, ok := old.HorseByID(h.ID)
+		if !ok {
+			o = &Horse{}
+			add(prefix+" status", nil, h.Status)
+		} else if o.Status != h.Status {
+			add(prefix+" status", o.Status, h.Status)
+		}
+		for _, s := range h.Shows {
+			if !hasShow(o.Shows, s) {
+				add(prefix+" show", nil, s)
+			}
+		}
+		if o.StartingPrice.Price.Cmp(&h.StartingPrice.Price) != 0 {
+			add(prefix+" starting price", formatPrice(&o.StartingPrice.Price), formatPrice(&h.StartingPrice.Price))
+		}
+		if or, nr := resultValue(o.Result), resultValue(h.Result); or != nr {
+			add(prefix+" result", or, nr)
+		}
+		if o.CasualtyReason != h.CasualtyReason {
+			add(prefix+" casualty", o.CasualtyReason, h.CasualtyReason)
+		}
+	}
+
+	if (old.Returns == nil) != (new.Returns == nil) {
+		add("returns", returnsValue(old.Returns), returnsValue(new.Returns))
+	}
+	return changes
+}
+
+// hasShow returns true if shows contain a show with the same timestamp and
+// betting market, see Show.Market.
+func hasShow(shows []Show, show Show) bool {
+	for _, s := range shows {
+		if s.Timestamp.Equal(show.Timestamp) && s.Market() == show.Market() {
+			return true
+		}
+	}
+	return false
+}
+
+// equalTimes returns true if both times are absent or represent the same time
+// instant.
+func equalTimes(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// timeValue returns dereferenced time or nil.
+func timeValue(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+// durationValue returns dereferenced duration or nil.
+func durationValue(d *time.Duration) interface{} {
+	if d == nil {
+		return nil
+	}
+	return *d
+}
+
+// resultValue returns dereferenced result or nil.
+func resultValue(r *Result) interface{} {
+	if r == nil {
+		return nil
+	}
+	return *r
+}
+
+// returnsValue returns returns or nil if returns are absent.
+func returnsValue(r *Returns) interface{} {
+	if r == nil {
+		return nil
+	}
+	return r
+}
+
+// PriceDirection is the direction of a price movement.
+type PriceDirection string
+
+// Possible price movement directions.
+const (
+	PriceSteamer PriceDirection = "Steamer" // Price has shortened
+	PriceDrifter PriceDirection = "Drifter" // Price has lengthened
+)
+
+// PriceChange is a price movement of a single horse between two states of a
+// race.
+type PriceChange struct {
+	HorseID   int            // The id of the horse
+	Name      string         // The name of the horse
+	Old       big.Rat        // Price of the latest show in the old race
+	New       big.Rat        // Price of the latest show in the new race
+	Direction PriceDirection // Whether the horse is a steamer or a drifter
+}
+
+// String returns price change description, e.g. "Mobsta shortened from 5/1 to
+// 7/2".
+func (c PriceChange) String() string {
+	verb := "shortened"
+	if c.Direction == PriceDrifter {
+		verb = "drifted"
+	}
+	return fmt.Sprintf("%s %s from %s to %s", c.Name, verb, c.Old.String(), c.New.String())
+}
+
+// PriceChanges returns price movements of horses between two states of the
+// same race. Price of the latest offered show of the first betting market in
+// each race is compared, see LatestShow. Horses not having an offered show in
+// either of the races or having unchanged prices are not reported. Changes are
+// returned in the order of horses in the new race.
+func PriceChanges(old, new Race) []PriceChange {
+	var changes []PriceChange
+	for i := range new.Horses {
+		h := &new.Horses[i]
+		n, ok := h.LatestShow(1)
+		if !ok {
+			continue
+		}
+		oh, ok := old.HorseByID(h.ID)
+		if !ok {
+			continue
+		}
+		o, ok := oh.LatestShow(1)
+		if !ok {
+			continue
+		}
+		direction := PriceSteamer
+		switch o.Price.Cmp(&n.Price) {
+		case 0:
+			continue
+		case -1:
+			direction = PriceDrifter
+		}
+		changes = append(changes, PriceChange{
+			HorseID:   h.ID,
+			Name:      h.Name,
+			Old:       o.Price,
+			New:       n.Price,
+			Direction: direction,
+		})
+	}
+	return changes
+}
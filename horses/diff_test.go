@@ -0,0 +1,150 @@
+package horses
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffRace(t *testing.T) {
+	before := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200071.xml")
+	off := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200072.xml")
+	result := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200080.xml")
+
+	assert.Empty(t, DiffRace(off, off))
+
+	changes := DiffRace(before, off)
+	require.NotEmpty(t, changes)
+	assert.Equal(t, Change{Path: "status", Old: RaceGoingBehind, New: RaceOff}, changes[0])
+	require.NotNil(t, off.OffTime)
+	assert.Contains(t, changes, Change{Path: "off time", Old: nil, New: *off.OffTime})
+
+	changes = DiffRace(off, result)
+	paths := make(map[string]Change)
+	for _, c := range changes {
+		paths[c.Path] = c
+	}
+	assert.Equal(t, RaceResult, paths["status"].New)
+	require.Contains(t, paths, "returns")
+	assert.Nil(t, paths["returns"].Old)
+	assert.Equal(t, result.Returns, paths["returns"].New)
+	winner, ok := result.Winner()
+	require.True(t, ok)
+	require.Contains(t, paths, "horse 2063105 result")
+	assert.Equal(t, 2063105, winner.ID)
+	assert.Nil(t, paths["horse 2063105 result"].Old)
+	assert.Equal(t, *winner.Result, paths["horse 2063105 result"].New)
+}
+
+func TestDiffRaceHorses(t *testing.T) {
+	old := Race{
+		Horses: []Horse{
+			{ID: 1, Status: HorseRunner},
+		},
+	}
+	new := Race{
+		Horses: []Horse{
+			{
+				ID:     1,
+				Status: HorseWithdrawn,
+				Shows:  []Show{{Timestamp: makeTime(t, "2018-04-16T16:10:07+01:00"), MarketNumber: 1, Price: makeRat(t, "5/1")}},
+			},
+			{ID: 2, Status: HorseRunner, StartingPrice: StartingPrice{Price: makeRat(t, "7/2")}},
+		},
+	}
+
+	assert.Equal(t, []Change{
+		{Path: "horse 1 status", Old: HorseRunner, New: HorseWithdrawn},
+		{Path: "horse 1 show", Old: nil, New: new.Horses[0].Shows[0]},
+		{Path: "horse 2 status", Old: nil, New: HorseRunner},
+		{Path: "horse 2 starting price", Old: "", New: "7/2"},
+	}, DiffRace(old, new))
+}
+
+func TestDiffRaceShowMarket(t *testing.T) {
+	ts := makeTime(t, "2018-04-16T16:10:07+01:00")
+	old := Race{Horses: []Horse{{ID: 1, Shows: []Show{{Timestamp: ts, Price: makeRat(t, "5/1")}}}}}
+	new := Race{Horses: []Horse{{ID: 1, Shows: []Show{{Timestamp: ts, MarketNumber: 1, Price: makeRat(t, "5/1")}}}}}
+	assert.Empty(t, DiffRace(old, new))
+
+	new.Horses[0].Shows[0].MarketNumber = 2
+	assert.Equal(t, []Change{
+		{Path: "horse 1 show", Old: nil, New: new.Horses[0].Shows[0]},
+	}, DiffRace(old, new))
+}
+
+func TestPriceChanges(t *testing.T) {
+	tests := []struct {
+		old, new string
+		changes  []string
+	}{
+		{
+			old:     "testdata/WindsorRule4BoardPrices/b20180416wnd16200005.xml",
+			new:     "testdata/WindsorRule4BoardPrices/b20180416wnd16200006.xml",
+			changes: []string{"Blaine shortened from 25/1 to 20/1"},
+		},
+		{
+			old:     "testdata/WindsorRule4BoardPrices/b20180416wnd16200007.xml",
+			new:     "testdata/WindsorRule4BoardPrices/b20180416wnd16200008.xml",
+			changes: []string{"Clear Spring drifted from 14/1 to 16/1"},
+		},
+		{
+			old: "testdata/WindsorRule4BoardPrices/b20180416wnd16200028.xml",
+			new: "testdata/WindsorRule4BoardPrices/b20180416wnd16200030.xml",
+			changes: []string{
+				"Nightingale Valley drifted from 14/1 to 16/1",
+				"Poet's Princess drifted from 8/1 to 9/1",
+			},
+		},
+		{
+			old: "testdata/WindsorRule4BoardPrices/b20180416wnd16200006.xml",
+			new: "testdata/WindsorRule4BoardPrices/b20180416wnd16200006.xml",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.new, func(t *testing.T) {
+			var changes []string
+			for _, c := range PriceChanges(testRace(t, test.old), testRace(t, test.new)) {
+				changes = append(changes, c.String())
+			}
+			assert.Equal(t, test.changes, changes)
+		})
+	}
+}
+
+func TestPriceChangesDirection(t *testing.T) {
+	ts := makeTime(t, "2018-04-16T16:10:07+01:00")
+	old := Race{
+		Horses: []Horse{
+			{ID: 1, Name: "A", Shows: []Show{{Timestamp: ts, Price: makeRat(t, "5/1")}}},
+			{ID: 2, Name: "B", Shows: []Show{{Timestamp: ts, Price: makeRat(t, "2/1")}}},
+			{ID: 3, Name: "C"},
+		},
+	}
+	later := ts.Add(time.Minute)
+	new := Race{
+		Horses: []Horse{
+			{ID: 1, Name: "A", Shows: []Show{
+				{Timestamp: ts, Price: makeRat(t, "5/1")},
+				{Timestamp: later, Price: makeRat(t, "7/2")},
+			}},
+			{ID: 2, Name: "B", Shows: []Show{
+				{Timestamp: ts, Price: makeRat(t, "2/1")},
+				{Timestamp: later, Price: makeRat(t, "5/2")},
+				{Timestamp: later.Add(time.Minute), NoOffers: true},
+				{Timestamp: later.Add(2 * time.Minute), MarketNumber: 2, Price: makeRat(t, "1/1")},
+			}},
+			{ID: 3, Name: "C", Shows: []Show{{Timestamp: later, Price: makeRat(t, "10/1")}}},
+		},
+	}
+
+	changes := PriceChanges(old, new)
+	require.Len(t, changes, 2)
+	assert.Equal(t, PriceChange{HorseID: 1, Name: "A", Old: makeRat(t, "5/1"), New: makeRat(t, "7/2"), Direction: PriceSteamer}, changes[0])
+	assert.Equal(t, "A shortened from 5/1 to 7/2", changes[0].String())
+	assert.Equal(t, PriceDrifter, changes[1].Direction)
+	assert.Equal(t, "B drifted from 2/1 to 5/2", changes[1].String())
+}
@@ -0,0 +1,72 @@
+package horses
+
+import (
+	"io/fs"
+	"strings"
+)
+
+// ParsedFile is a result of parsing a single feed file by ParseDir. Exactly
+// one of Racing and Card is set if the file was parsed successfully.
+type ParsedFile struct {
+	Path   string          // Path of the file in the walked file system
+	Racing *RacingFile     // Parsed Racing document
+	Card   *RacingCardFile // Parsed RacingCard document
+	Err    error           // Error parsing the file
+}
+
+// ParseDir walks directory tree rooted at root and parses every feed file
+// found. Files are classified by IsRacingFile and IsRacingCardFile, only files
+// having .xml extension are parsed. Gzip compressed files having extra .gz
+// extension are parsed too, other files are skipped. Files that fail to parse
+// are returned with Err set, walking is not aborted. Error is returned only if
+// walking the directory fails.
+func ParseDir(fsys fs.FS, root string) ([]ParsedFile, error) {
+	var files []ParsedFile
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		name := strings.TrimSuffix(d.Name(), ".gz")
+		if !strings.HasSuffix(name, ".xml") {
+			return nil
+		}
+		file := ParsedFile{Path: p}
+		switch {
+		case IsRacingFile(name):
+			file.Err = parseFSFile(fsys, p, func(f fs.File) (err error) {
+				file.Racing, err = ParseRacingReader(f)
+				return err
+			})
+		case IsRacingCardFile(name):
+			file.Err = parseFSFile(fsys, p, func(f fs.File) (err error) {
+				file.Card, err = ParseRacingCardReader(f)
+				return err
+			})
+		default:
+			return nil
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// parseFSFile opens a single file of the file system and parses it using the
+// given function.
+func parseFSFile(fsys fs.FS, name string, parse func(fs.File) error) error {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := parse(f); err != nil {
+		return &fs.PathError{Op: "parse", Path: name, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package horses
+
+import (
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDir(t *testing.T) {
+	racing, err := ioutil.ReadFile("testdata/feed/b20181128wth12150045.xml")
+	require.NoError(t, err)
+	card, err := ioutil.ReadFile("testdata/feed/c20190227rsh.xml")
+	require.NoError(t, err)
+	gzipped, err := ioutil.ReadFile("testdata/gzip/b20181128wth12150045.xml.gz")
+	require.NoError(t, err)
+
+	fsys := fstest.MapFS{
+		"drop/b20181128wth12150045.xml":         {Data: racing},
+		"drop/c20190227rsh.xml":                 {Data: card},
+		"drop/late/b20181128wth12150046.xml":    {Data: []byte("<HorseRacing><Meeting")},
+		"drop/late/b20181128wth12150047.xml.gz": {Data: gzipped},
+		"drop/b20181128wth12150045.json":        {Data: []byte("{}")},
+		"drop/Edge Cases.xls":                   {Data: []byte("")},
+	}
+
+	files, err := ParseDir(fsys, "drop")
+	require.NoError(t, err)
+	require.Len(t, files, 4)
+	expected := parseTestFile(t, "testdata/feed/b20181128wth12150045.xml")
+
+	assert.Equal(t, "drop/b20181128wth12150045.xml", files[0].Path)
+	require.NoError(t, files[0].Err)
+	assert.Equal(t, expected, files[0].Racing)
+	assert.Nil(t, files[0].Card)
+
+	assert.Equal(t, "drop/c20190227rsh.xml", files[1].Path)
+	require.NoError(t, files[1].Err)
+	assert.Nil(t, files[1].Racing)
+	assert.Equal(t, parseTestCardFile(t, "testdata/feed/c20190227rsh.xml"), files[1].Card)
+
+	assert.Equal(t, "drop/late/b20181128wth12150046.xml", files[2].Path)
+	var pathErr *fs.PathError
+	require.ErrorAs(t, files[2].Err, &pathErr)
+	assert.Equal(t, "drop/late/b20181128wth12150046.xml", pathErr.Path)
+	assert.Nil(t, files[2].Racing)
+
+	assert.Equal(t, "drop/late/b20181128wth12150047.xml.gz", files[3].Path)
+	require.NoError(t, files[3].Err)
+	assert.Equal(t, expected, files[3].Racing)
+
+	_, err = ParseDir(fsys, "missing")
+	assert.Error(t, err)
+}
+
+func TestParseDirFeed(t *testing.T) {
+	files, err := ParseDir(os.DirFS("testdata"), "feed")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, f := range files {
+		assert.NoError(t, f.Err, f.Path)
+		assert.True(t, (f.Racing == nil) != (f.Card == nil), f.Path)
+	}
+}
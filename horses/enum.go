@@ -0,0 +1,345 @@
+package horses
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s MeetingStatus) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *MeetingStatus) UnmarshalText(text []byte) error {
+	v := MeetingStatus(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid Meeting status value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s RaceStatus) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *RaceStatus) UnmarshalText(text []byte) error {
+	v := RaceStatus(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid Race status value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s StewardsStatus) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *StewardsStatus) UnmarshalText(text []byte) error {
+	v := StewardsStatus(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid Stewards status value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s HorseStatus) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *HorseStatus) UnmarshalText(text []byte) error {
+	v := HorseStatus(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid Horse status value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s CardMeetingStatus) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *CardMeetingStatus) UnmarshalText(text []byte) error {
+	v := CardMeetingStatus(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid card Meeting status value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s CardHorseStatus) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *CardHorseStatus) UnmarshalText(text []byte) error {
+	v := CardHorseStatus(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid card Horse status value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// String implements fmt.Stringer interface. National Hunt Flat races are
+// spelled out in full, other race types are returned as is.
+func (s RaceType) String() string {
+	if s == RaceNationalHuntFlat {
+		return "National Hunt Flat"
+	}
+	return string(s)
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s RaceType) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *RaceType) UnmarshalText(text []byte) error {
+	v := RaceType(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid Race type value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s TrackType) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *TrackType) UnmarshalText(text []byte) error {
+	v := TrackType(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid Track type value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s CasualtyReason) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as no casualty.
+func (s *CasualtyReason) UnmarshalText(text []byte) error {
+	v := CasualtyReason(text)
+	if !v.isValid() && v != CasualtyUnknown {
+		return fmt.Errorf("invalid Casualty reason value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s ToteType) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *ToteType) UnmarshalText(text []byte) error {
+	v := ToteType(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid Tote type value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler interface.
+func (s BetType) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface. Unknown values
+// are rejected, empty value is accepted as unset.
+func (s *BetType) UnmarshalText(text []byte) error {
+	v := BetType(text)
+	if v != "" && !v.isValid() {
+		return fmt.Errorf("invalid Bet type value: %s", text)
+	}
+	*s = v
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface. Feed values are
+// kept as is, so that a status introduced by PA does not fail the whole
+// document. Unknown values are reported by Validate.
+func (s *RaceStatus) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = RaceStatus(attr.Value)
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface. Value is checked
+// by the Meeting element unmarshaler, unknown meeting status fails the document.
+func (s *MeetingStatus) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = MeetingStatus(attr.Value)
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface, see
+// RaceStatus.UnmarshalXMLAttr.
+func (s *StewardsStatus) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = StewardsStatus(attr.Value)
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface, see
+// RaceStatus.UnmarshalXMLAttr.
+func (s *HorseStatus) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = HorseStatus(attr.Value)
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface, see
+// RaceStatus.UnmarshalXMLAttr.
+func (s *CardMeetingStatus) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = CardMeetingStatus(attr.Value)
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface, see
+// RaceStatus.UnmarshalXMLAttr.
+func (s *CardHorseStatus) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = CardHorseStatus(attr.Value)
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface, see
+// RaceStatus.UnmarshalXMLAttr.
+func (s *RaceType) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = RaceType(attr.Value)
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface, see
+// RaceStatus.UnmarshalXMLAttr.
+func (s *TrackType) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = TrackType(attr.Value)
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface. Feed values are
+// kept as is, they are converted by parseCasualtyReason.
+func (s *CasualtyReason) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = CasualtyReason(attr.Value)
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface, see
+// RaceStatus.UnmarshalXMLAttr.
+func (s *ToteType) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = ToteType(attr.Value)
+	return nil
+}
+
+// UnmarshalXMLAttr implements xml.UnmarshalerAttr interface, see
+// RaceStatus.UnmarshalXMLAttr.
+func (s *BetType) UnmarshalXMLAttr(attr xml.Attr) error {
+	*s = BetType(attr.Value)
+	return nil
+}
+
+func (s CardMeetingStatus) isValid() bool {
+	switch s {
+	case CardMeetingDormant,
+		CardMeetingInspection,
+		CardMeetingAbandoned:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s CardHorseStatus) isValid() bool {
+	switch s {
+	case CardHorseRunner,
+		CardHorseDoubtful,
+		CardHorseNonRunner:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s RaceType) isValid() bool {
+	switch s {
+	case RaceFlat,
+		RaceHurdle,
+		RaceChase,
+		RaceNationalHuntFlat:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s TrackType) isValid() bool {
+	switch s {
+	case TrackTurf,
+		TrackFibresand,
+		TrackPolytrack,
+		TrackEquitrack,
+		TrackDirt,
+		TrackSand,
+		TrackAllWeather:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s ToteType) isValid() bool {
+	switch s {
+	case ToteWin,
+		TotePlace,
+		ToteShow,
+		ToteExacta,
+		ToteQuinella,
+		ToteTrifecta,
+		ToteSuperfecta,
+		ToteSwinger:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s BetType) isValid() bool {
+	switch s {
+	case BetTypeCSF,
+		BetTypeReverseForecast,
+		BetTypeTricast:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,147 @@
+package horses
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumJSON(t *testing.T) {
+	race := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200080.xml")
+	blob, err := json.Marshal(race)
+	require.NoError(t, err)
+
+	var out struct {
+		Status   RaceStatus
+		Stewards StewardsStatus
+		Horses   []struct {
+			Status HorseStatus
+		}
+	}
+	require.NoError(t, json.Unmarshal(blob, &out))
+	assert.Equal(t, race.Status, out.Status)
+	assert.Equal(t, race.Stewards, out.Stewards)
+	require.Len(t, out.Horses, len(race.Horses))
+	for i, h := range race.Horses {
+		assert.Equal(t, h.Status, out.Horses[i].Status)
+	}
+
+	tests := []struct {
+		blob  string
+		value interface{}
+	}{
+		{blob: `"Running"`, value: new(RaceStatus)},
+		{blob: `"Maybe"`, value: new(StewardsStatus)},
+		{blob: `"Scratched"`, value: new(HorseStatus)},
+		{blob: `"Postponed"`, value: new(MeetingStatus)},
+		{blob: `"Flat"`, value: new(CardHorseStatus)},
+		{blob: `"Steeplechase"`, value: new(RaceType)},
+		{blob: `"Grass"`, value: new(TrackType)},
+		{blob: `"Slipped"`, value: new(CasualtyReason)},
+		{blob: `"Jackpot"`, value: new(ToteType)},
+		{blob: `"Tote"`, value: new(BetType)},
+	}
+	for _, test := range tests {
+		assert.Error(t, json.Unmarshal([]byte(test.blob), test.value), test.blob)
+	}
+
+	var track TrackType
+	require.NoError(t, json.Unmarshal([]byte(`"AllWeather"`), &track))
+	assert.Equal(t, TrackAllWeather, track)
+
+	for _, reason := range []CasualtyReason{NoCasualty, PulledUp, CasualtyUnknown} {
+		blob, err := json.Marshal(reason)
+		require.NoError(t, err)
+		var out CasualtyReason
+		require.NoError(t, json.Unmarshal(blob, &out), string(blob))
+		assert.Equal(t, reason, out)
+	}
+	var tote ToteType
+	require.NoError(t, json.Unmarshal([]byte(`"Exacta"`), &tote))
+	assert.Equal(t, ToteExacta, tote)
+	var bet BetType
+	require.NoError(t, json.Unmarshal([]byte(`"Tricast"`), &bet))
+	assert.Equal(t, BetTypeTricast, bet)
+}
+
+func TestEnumXMLUnknownValues(t *testing.T) {
+	blob, err := ioutil.ReadFile("testdata/WindsorRule4BoardPrices/b20180416wnd16200080.xml")
+	require.NoError(t, err)
+	blob = bytes.Replace(blob, []byte(`status="Result"`), []byte(`status="Stalls Loading"`), 1)
+	blob = bytes.Replace(blob, []byte(`stewards="None"`), []byte(`stewards="Pending"`), 1)
+	// first two runners in the file
+	blob = bytes.Replace(blob, []byte(`status="Runner"`), []byte(`status="Scratched"`), 1)
+	blob = bytes.Replace(blob, []byte(` status="Runner"`), nil, 1)
+
+	obj, err := ParseRacingFile(blob)
+	require.NoError(t, err)
+	race := obj.Meetings[0].Races[0]
+	assert.Equal(t, RaceStatus("Stalls Loading"), race.Status)
+	assert.Equal(t, StewardsStatus("Pending"), race.Stewards)
+	err = obj.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid status: "Stalls Loading"`)
+	assert.Contains(t, err.Error(), `invalid stewards status: "Pending"`)
+	assert.Equal(t, HorseStatus("Scratched"), race.Horses[0].Status)
+	assert.Equal(t, HorseStatus(""), race.Horses[1].Status)
+	assert.Contains(t, err.Error(), `horse 1761741: invalid status: "Scratched"`)
+	assert.Contains(t, err.Error(), fmt.Sprintf(`horse %d: invalid status: ""`, race.Horses[1].ID))
+	assert.Len(t, err.(ValidationError), 4)
+
+	var horse xmlCardHorse
+	require.NoError(t, xml.Unmarshal([]byte(`<Horse id="1" status="Balloted"/>`), &horse))
+	assert.Equal(t, CardHorseStatus("Balloted"), horse.Status)
+	var meeting xmlCardMeeting
+	require.NoError(t, xml.Unmarshal([]byte(`<Meeting id="1" status="Postponed" date="20180414"/>`), &meeting))
+	assert.Equal(t, CardMeetingStatus("Postponed"), meeting.Status)
+	card := RacingCardFile{CardMeeting(meeting)}
+	card[0].Races = []CardRace{{ID: 2, Horses: []CardHorse{CardHorse(horse)}}}
+	err = card.Validate()
+	require.Error(t, err)
+	assert.Len(t, err.(ValidationError), 2)
+}
+
+func TestDividendTypes(t *testing.T) {
+	toteTypes := []ToteType{ToteWin, TotePlace, ToteShow, ToteExacta, ToteQuinella, ToteTrifecta, ToteSuperfecta, ToteSwinger}
+	for _, typ := range toteTypes {
+		var tote xmlTote
+		blob := `<Tote type="` + string(typ) + `" currency="GBP" dividend="1.50" stake="1"/>`
+		require.NoError(t, xml.Unmarshal([]byte(blob), &tote))
+		assert.Equal(t, typ, tote.Type)
+		assert.True(t, tote.Type.isValid(), typ)
+	}
+	betTypes := []BetType{BetTypeCSF, BetTypeReverseForecast, BetTypeTricast}
+	for _, typ := range betTypes {
+		var bet xmlBet
+		blob := `<Bet type="` + string(typ) + `" currency="GBP" dividend="1.50"/>`
+		require.NoError(t, xml.Unmarshal([]byte(blob), &bet))
+		assert.Equal(t, typ, bet.Type)
+		assert.True(t, bet.Type.isValid(), typ)
+	}
+
+	// unknown types are preserved
+	var tote xmlTote
+	require.NoError(t, xml.Unmarshal([]byte(`<Tote type="Jackpot" currency="GBP" dividend="1.50" stake="1"/>`), &tote))
+	assert.Equal(t, ToteType("Jackpot"), tote.Type)
+	assert.False(t, tote.Type.isValid())
+	var bet xmlBet
+	require.NoError(t, xml.Unmarshal([]byte(`<Bet type="Tote" currency="GBP" dividend="1.50"/>`), &bet))
+	assert.Equal(t, BetType("Tote"), bet.Type)
+	assert.False(t, bet.Type.isValid())
+}
+
+func TestRaceTypeString(t *testing.T) {
+	assert.Equal(t, "National Hunt Flat", RaceNationalHuntFlat.String())
+	assert.Equal(t, "Chase", RaceChase.String())
+	assert.Equal(t, "National Hunt Flat", fmt.Sprint(RaceNationalHuntFlat))
+
+	blob, err := json.Marshal(RaceNationalHuntFlat)
+	require.NoError(t, err)
+	assert.Equal(t, `"N_H_Flat"`, string(blob))
+}
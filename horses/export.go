@@ -0,0 +1,65 @@
+package horses
+
+import (
+	"math/big"
+	"time"
+)
+
+// RunnerRow is a flat, JSON friendly representation of a single runner
+// together with its meeting and race details. Field order defines JSON column
+// order and must not be changed.
+type RunnerRow struct {
+	MeetingID   int         `json:"meetingId"`   // The internal identifier for the meeting
+	Course      string      `json:"course"`      // The course at which the meeting is being held
+	Country     string      `json:"country"`     // The country in which the meeting is being held
+	Date        time.Time   `json:"date"`        // The date on which the meeting is being held
+	RaceID      int         `json:"raceId"`      // The internal identifier for the race
+	StartTime   time.Time   `json:"startTime"`   // Date and time when race is scheduled to start
+	HorseID     int         `json:"horseId"`     // The internal identifier for the horse
+	Name        string      `json:"name"`        // The name of the horse
+	ClothNumber int         `json:"clothNumber"` // Saddlecloth or racecard number of horse
+	Status      HorseStatus `json:"status"`      // Horse status regarding this race
+	Price       string      `json:"price"`       // Fractional starting price or latest show, empty if unknown
+}
+
+// RunnerRows returns a flat list of all runners in the meeting, in race and
+// feed order.
+func (m *Meeting) RunnerRows() []RunnerRow {
+	var rows []RunnerRow
+	for _, r := range m.Races {
+		for _, h := range r.Horses {
+			rows = append(rows, RunnerRow{
+				MeetingID:   m.ID,
+				Course:      m.Course,
+				Country:     m.Country,
+				Date:        m.Date,
+				RaceID:      r.ID,
+				StartTime:   r.StartTime,
+				HorseID:     h.ID,
+				Name:        h.Name,
+				ClothNumber: h.ClothNumber,
+				Status:      h.Status,
+				Price:       h.latestPrice(),
+			})
+		}
+	}
+	return rows
+}
+
+// latestPrice returns current price of the horse formatted as a fraction, see
+// CurrentPrice.
+func (h *Horse) latestPrice() string {
+	if p, ok := h.CurrentPrice(); ok {
+		return formatPrice(p)
+	}
+	return ""
+}
+
+// formatPrice returns fractional price representation, e.g. "11/4". Empty
+// string is returned for zero price.
+func formatPrice(p *big.Rat) string {
+	if p.Sign() == 0 {
+		return ""
+	}
+	return p.String()
+}
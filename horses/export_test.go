@@ -0,0 +1,27 @@
+package horses
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeetingRunnerRows(t *testing.T) {
+	obj := parseTestFile(t, "testdata/feed/b20181128wth12150045.xml")
+	rows := obj.Meetings[0].RunnerRows()
+	require.Len(t, rows, 10)
+
+	blob, err := json.Marshal(rows[:2])
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+	  {"meetingId":104930,"course":"Wetherby","country":"England","date":"2018-11-28T00:00:00Z","raceId":854412,"startTime":"2018-11-28T12:15:00Z","horseId":2358957,"name":"Alexanderthegreat","clothNumber":1,"status":"Runner","price":"13/8"},
+	  {"meetingId":104930,"course":"Wetherby","country":"England","date":"2018-11-28T00:00:00Z","raceId":854412,"startTime":"2018-11-28T12:15:00Z","horseId":2342636,"name":"Alliteration","clothNumber":2,"status":"Runner","price":"4/1"}
+	]`, string(blob))
+
+	// Column order must be stable
+	blob, err = json.Marshal(rows[0])
+	require.NoError(t, err)
+	assert.Equal(t, `{"meetingId":104930,"course":"Wetherby","country":"England","date":"2018-11-28T00:00:00Z","raceId":854412,"startTime":"2018-11-28T12:15:00Z","horseId":2358957,"name":"Alexanderthegreat","clothNumber":1,"status":"Runner","price":"13/8"}`, string(blob))
+}
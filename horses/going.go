@@ -0,0 +1,40 @@
+package horses
+
+import "strings"
+
+// goingScale maps going descriptions to ordinal values from the firmest to the
+// softest ground. All-weather descriptions share the scale, standard going is
+// placed next to good.
+var goingScale = map[string]int{
+	"hard":             0,
+	"firm":             0,
+	"good to firm":     1,
+	"good":             2,
+	"good to yielding": 3,
+	"good to soft":     3,
+	"yielding":         3,
+	"yielding to soft": 4,
+	"soft":             4,
+	"soft to heavy":    5,
+	"heavy":            6,
+
+	"fast":             0,
+	"standard to fast": 1,
+	"standard":         2,
+	"standard to slow": 3,
+	"slow":             4,
+}
+
+// GoingScale maps going description to an ordinal value, from 0 for firm to 6
+// for heavy ground, so going of different races can be compared. All-weather
+// going is mapped to the same scale, from 0 for fast to 4 for slow with
+// standard equal to good. Full descriptions are accepted, only the leading
+// part is used, e.g. "Good (Good to Soft in places)" is mapped as "Good".
+// False is returned for unknown descriptions.
+func GoingScale(s string) (int, bool) {
+	if i := strings.Index(s, "("); i >= 0 {
+		s = s[:i]
+	}
+	v, ok := goingScale[strings.ToLower(strings.TrimSpace(s))]
+	return v, ok
+}
@@ -0,0 +1,46 @@
+package horses
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoingScale(t *testing.T) {
+	tests := []struct {
+		going string
+		scale int
+		ok    bool
+	}{
+		{going: "Firm", scale: 0, ok: true},
+		{going: "Good to Firm", scale: 1, ok: true},
+		{going: "Good", scale: 2, ok: true},
+		{going: "Good to Soft", scale: 3, ok: true},
+		{going: "Soft", scale: 4, ok: true},
+		{going: "Soft to Heavy", scale: 5, ok: true},
+		{going: "Heavy", scale: 6, ok: true},
+		{going: "Standard", scale: 2, ok: true},
+		{going: "Standard to Slow", scale: 3, ok: true},
+		{going: "good to soft", scale: 3, ok: true},
+		{going: "Good (Good to Soft in places)", scale: 2, ok: true},
+		{going: "Heavy (Soft in places)", scale: 6, ok: true},
+		{going: "", ok: false},
+		{going: "Sloppy", ok: false},
+	}
+
+	for _, test := range tests {
+		scale, ok := GoingScale(test.going)
+		assert.Equal(t, test.ok, ok, test.going)
+		assert.Equal(t, test.scale, scale, test.going)
+	}
+}
+
+func TestGoingScaleFeed(t *testing.T) {
+	race := testRace(t, "testdata/feed/b20181128wth12150045.xml")
+	brief, ok := GoingScale(race.GoingBrief)
+	assert.True(t, ok)
+	full, ok := GoingScale(race.GoingFull)
+	assert.True(t, ok)
+	assert.Equal(t, 3, brief)
+	assert.Equal(t, brief, full)
+}
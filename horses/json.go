@@ -0,0 +1,46 @@
+package horses
+
+import "encoding/json"
+
+// MarshalJSON implements json.Marshaler interface. WinTime is written in
+// seconds.
+func (r Race) MarshalJSON() ([]byte, error) {
+	type race Race
+	data := struct {
+		race
+		WinTime *float64
+	}{
+		race: race(r),
+	}
+	if r.WinTime != nil {
+		seconds := r.WinTime.Seconds()
+		data.WinTime = &seconds
+	}
+	return json.Marshal(data)
+}
+
+// MarshalJSON implements json.Marshaler interface. Price is written as a
+// fractional string.
+func (sp StartingPrice) MarshalJSON() ([]byte, error) {
+	type startingPrice StartingPrice
+	return json.Marshal(struct {
+		startingPrice
+		Price string
+	}{
+		startingPrice: startingPrice(sp),
+		Price:         formatPrice(&sp.Price),
+	})
+}
+
+// MarshalJSON implements json.Marshaler interface. Price is written as a
+// fractional string.
+func (s Show) MarshalJSON() ([]byte, error) {
+	type show Show
+	return json.Marshal(struct {
+		show
+		Price string
+	}{
+		show:  show(s),
+		Price: formatPrice(&s.Price),
+	})
+}
@@ -0,0 +1,77 @@
+package horses
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestMarshalJSONGolden(t *testing.T) {
+	tests := []struct {
+		file   string
+		golden string
+	}{
+		{
+			file:   "testdata/feed/b20181128wth12150045.xml",
+			golden: "testdata/golden/b20181128wth12150045.json",
+		},
+		{
+			file:   "testdata/Lingfield/b20180414lin17400007.xml",
+			golden: "testdata/golden/b20180414lin17400007.json",
+		},
+	}
+
+	for _, test := range tests {
+		obj := parseTestFile(t, test.file)
+		blob, err := json.MarshalIndent(obj, "", "  ")
+		require.NoError(t, err, test.file)
+		if *update {
+			require.NoError(t, ioutil.WriteFile(test.golden, blob, 0644))
+		}
+		expected, err := ioutil.ReadFile(test.golden)
+		require.NoError(t, err, test.golden)
+		assert.Equal(t, string(expected), string(blob), test.golden)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	tests := []struct {
+		obj  interface{}
+		json string
+	}{
+		{
+			obj:  StartingPrice{Price: *big.NewRat(13, 8), FavouritePosition: 1, FavouriteJoint: 1},
+			json: `{"Price":"13/8","FavouritePosition":1,"FavouriteJoint":1}`,
+		},
+		{
+			obj:  StartingPrice{Price: *big.NewRat(2, 1)},
+			json: `{"Price":"2/1","FavouritePosition":0,"FavouriteJoint":0}`,
+		},
+		{
+			obj:  StartingPrice{},
+			json: `{"Price":"","FavouritePosition":0,"FavouriteJoint":0}`,
+		},
+		{
+			obj:  Show{Timestamp: time.Date(2018, 4, 14, 17, 33, 20, 0, time.UTC), MarketNumber: 1, Price: *big.NewRat(14, 1)},
+			json: `{"Timestamp":"2018-04-14T17:33:20Z","MarketNumber":1,"NoOffers":false,"Price":"14/1"}`,
+		},
+		{
+			obj:  UnitsValueText{Units: "lbs", Value: 135, Text: "9st 9lbs"},
+			json: `{"Units":"lbs","Value":135,"Text":"9st 9lbs"}`,
+		},
+	}
+
+	for _, test := range tests {
+		blob, err := json.Marshal(test.obj)
+		require.NoError(t, err)
+		assert.JSONEq(t, test.json, string(blob))
+	}
+}
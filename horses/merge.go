@@ -0,0 +1,139 @@
+package horses
+
+import "sort"
+
+// MergeRace combines two states of the same race received in different
+// documents. Races are matched by ID, if IDs differ new race is returned
+// unchanged. Fields of the race with the higher revision are used (new wins
+// when revisions are equal), sub objects omitted by it are preserved from the
+// other race:
+//
+//   - horses are matched by ID, horses missing from the higher revision are
+//     kept;
+//   - horse shows are accumulated from both races, shows with the same
+//     timestamp and market number are not repeated, shows are ordered by
+//     timestamp;
+//   - horse result and starting price are carried forward unless present in
+//     the higher revision;
+//   - betting markets and returns are carried forward unless present in the
+//     higher revision, market suspensions are accumulated from both races.
+func MergeRace(old, new Race) Race {
+	if old.ID != new.ID {
+		return new
+	}
+	hi, lo := new, old
+	if old.Revision > new.Revision {
+		hi, lo = old, new
+	}
+
+	merged := hi
+	merged.Horses = mergeHorses(hi.Horses, lo.Horses)
+	if len(merged.BetMarkets) == 0 {
+		merged.BetMarkets = lo.BetMarkets
+	} else {
+		merged.BetMarkets = mergeBetMarkets(hi.BetMarkets, lo.BetMarkets)
+	}
+	if merged.Returns == nil {
+		merged.Returns = lo.Returns
+	}
+	return merged
+}
+
+// mergeHorses merges horses of the higher revision race hi with the horses of
+// the lower revision race lo.
+func mergeHorses(hi, lo []Horse) []Horse {
+	horses := make([]Horse, 0, len(hi)+len(lo))
+	horses = append(horses, hi...)
+	for _, old := range lo {
+		found := false
+		for i := range horses {
+			if horses[i].ID != old.ID {
+				continue
+			}
+			found = true
+			if horses[i].Result == nil {
+				horses[i].Result = old.Result
+			}
+			if horses[i].StartingPrice.Price.Sign() == 0 {
+				horses[i].StartingPrice = old.StartingPrice
+			}
+			horses[i].Shows = mergeShows(horses[i].Shows, old.Shows)
+			break
+		}
+		if !found {
+			horses = append(horses, old)
+		}
+	}
+	return horses
+}
+
+// mergeShows returns shows of hi extended with the shows of lo that are not
+// present in hi.
+func mergeShows(hi, lo []Show) []Show {
+	if len(lo) == 0 {
+		return hi
+	}
+	shows := make([]Show, 0, len(hi)+len(lo))
+	shows = append(shows, hi...)
+	for _, old := range lo {
+		found := false
+		for _, s := range hi {
+			if s.Timestamp.Equal(old.Timestamp) && s.Market() == old.Market() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			shows = append(shows, old)
+		}
+	}
+	sort.SliceStable(shows, func(i, j int) bool {
+		return shows[i].Timestamp.Before(shows[j].Timestamp)
+	})
+	return shows
+}
+
+// mergeBetMarkets returns betting markets of hi having suspensions extended
+// with the suspensions of the same markets in lo.
+func mergeBetMarkets(hi, lo []BetMarket) []BetMarket {
+	markets := make([]BetMarket, len(hi))
+	copy(markets, hi)
+	for i := range markets {
+		for _, old := range lo {
+			if old.MarketNumber == markets[i].MarketNumber {
+				markets[i].Suspensions = mergeSuspensions(markets[i].Suspensions, old.Suspensions)
+				break
+			}
+		}
+	}
+	return markets
+}
+
+// mergeSuspensions returns suspensions of hi extended with the suspensions of
+// lo that are not present in hi. Matched withdrawals are carried forward.
+func mergeSuspensions(hi, lo []Suspension) []Suspension {
+	if len(lo) == 0 {
+		return hi
+	}
+	suspensions := make([]Suspension, 0, len(hi)+len(lo))
+	suspensions = append(suspensions, hi...)
+	for _, old := range lo {
+		found := false
+		for i := range suspensions {
+			if suspensions[i].Time.Equal(old.Time) {
+				found = true
+				if suspensions[i].Withdrawn == nil {
+					suspensions[i].Withdrawn = old.Withdrawn
+				}
+				break
+			}
+		}
+		if !found {
+			suspensions = append(suspensions, old)
+		}
+	}
+	sort.SliceStable(suspensions, func(i, j int) bool {
+		return suspensions[i].Time.Before(suspensions[j].Time)
+	})
+	return suspensions
+}
@@ -0,0 +1,91 @@
+package horses
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRace(t *testing.T, file string) Race {
+	rf := parseTestFile(t, file)
+	require.Len(t, rf.Meetings, 1)
+	require.Len(t, rf.Meetings[0].Races, 1)
+	return rf.Meetings[0].Races[0]
+}
+
+func countShows(r Race) int {
+	n := 0
+	for _, h := range r.Horses {
+		n += len(h.Shows)
+	}
+	return n
+}
+
+func TestMergeRace(t *testing.T) {
+	rev10 := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200010.xml")
+	rev11 := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200011.xml")
+	rev80 := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200080.xml")
+	require.NotNil(t, rev80.Returns)
+
+	// consecutive revisions
+	merged := MergeRace(rev10, rev11)
+	assert.Equal(t, rev11.Revision, merged.Revision)
+	assert.Equal(t, countShows(rev11), countShows(merged))
+
+	// lower revision received out of order does not override
+	merged = MergeRace(rev80, rev10)
+	assert.Equal(t, rev80, merged)
+
+	// later partial message omitting results, returns and show history
+	partial := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200080.xml")
+	partial.Revision++
+	partial.Returns = nil
+	for i := range partial.Horses {
+		partial.Horses[i].Result = nil
+		if n := len(partial.Horses[i].Shows); n > 0 {
+			partial.Horses[i].Shows = partial.Horses[i].Shows[n-1:]
+		}
+	}
+	merged = MergeRace(rev80, partial)
+	assert.Equal(t, partial.Revision, merged.Revision)
+	assert.Equal(t, rev80.Returns, merged.Returns)
+	require.Len(t, merged.Horses, len(rev80.Horses))
+	for i := range merged.Horses {
+		assert.Equal(t, rev80.Horses[i].Result, merged.Horses[i].Result)
+		assert.Equal(t, rev80.Horses[i].Shows, merged.Horses[i].Shows)
+	}
+
+	// races with different IDs are not merged
+	other := testRace(t, "testdata/feed/b20181128wth12150045.xml")
+	assert.Equal(t, other, MergeRace(rev80, other))
+}
+
+func TestMergeShows(t *testing.T) {
+	ts := makeTime(t, "2018-04-16T16:10:07+01:00")
+	hi := []Show{{Timestamp: ts, MarketNumber: 1, Price: makeRat(t, "5/1")}}
+	lo := []Show{
+		{Timestamp: ts, Price: makeRat(t, "5/1")},
+		{Timestamp: ts, MarketNumber: 2, Price: makeRat(t, "6/1")},
+	}
+	assert.Equal(t, []Show{hi[0], lo[1]}, mergeShows(hi, lo))
+}
+
+func TestMergeRaceSuspensions(t *testing.T) {
+	var merged Race
+	for _, seq := range []string{"04", "05", "06", "07", "08", "09", "12", "21"} {
+		race := testRace(t, "testdata/EdgeCases/b20131028ban135000"+seq+".xml")
+		merged = MergeRace(merged, race)
+	}
+
+	require.NotEmpty(t, merged.BetMarkets)
+	assert.Equal(t, []Suspension{
+		{
+			Time:      makeTime(t, "2013-10-29T10:27:08+00:00"),
+			Withdrawn: &HorseRef{ID: 1868709, Name: "Chase The Spud", Bred: "GB"},
+		},
+		{
+			Time: makeTime(t, "2013-10-29T10:46:36+00:00"),
+		},
+	}, merged.BetMarkets[0].Suspensions)
+}
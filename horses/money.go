@@ -0,0 +1,40 @@
+package horses
+
+import "fmt"
+
+// currencySymbols maps currency codes used in the feed to their symbols.
+var currencySymbols = map[string]string{
+	"GBP": "£",
+	"EUR": "€",
+	"USD": "$",
+	"AUD": "A$",
+	"ZAR": "R",
+}
+
+// Add returns the sum of two money values. Zero money value having no
+// currency is treated as zero in any currency, so it can be used to start a
+// sum. Error is returned if currencies of the values do not match.
+func (m MoneyValue) Add(that MoneyValue) (MoneyValue, error) {
+	switch {
+	case m.Currency == "" && m.Amount.IsZero():
+		return that, nil
+	case that.Currency == "" && that.Amount.IsZero():
+		return m, nil
+	case m.Currency != that.Currency:
+		return MoneyValue{}, fmt.Errorf("adding %s money to %s money", that.Currency, m.Currency)
+	}
+	return MoneyValue{Currency: m.Currency, Amount: m.Amount.Add(that.Amount)}, nil
+}
+
+// String returns money value prefixed with the currency symbol, e.g. "£3752"
+// or "€1250.50". Currency code is used if symbol is not known, e.g. "HKD 500",
+// and amount alone if money value has no currency.
+func (m MoneyValue) String() string {
+	if symbol, ok := currencySymbols[m.Currency]; ok {
+		return symbol + m.Amount.String()
+	}
+	if m.Currency == "" {
+		return m.Amount.String()
+	}
+	return m.Currency + " " + m.Amount.String()
+}
@@ -0,0 +1,85 @@
+package horses
+
+import (
+	"testing"
+
+	"github.com/advbet/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoneyValueAdd(t *testing.T) {
+	tests := []struct {
+		a, b     MoneyValue
+		expected MoneyValue
+		err      bool
+	}{
+		{
+			a:        MoneyValue{Currency: "GBP", Amount: decimal.FromInt(7021)},
+			b:        MoneyValue{Currency: "GBP", Amount: decimal.New(5050, -2)},
+			expected: MoneyValue{Currency: "GBP", Amount: decimal.New(707150, -2)},
+		},
+		{
+			a:        MoneyValue{},
+			b:        MoneyValue{Currency: "EUR", Amount: decimal.FromInt(2500)},
+			expected: MoneyValue{Currency: "EUR", Amount: decimal.FromInt(2500)},
+		},
+		{
+			a:        MoneyValue{Currency: "EUR", Amount: decimal.FromInt(2500)},
+			b:        MoneyValue{},
+			expected: MoneyValue{Currency: "EUR", Amount: decimal.FromInt(2500)},
+		},
+		{
+			a:   MoneyValue{Currency: "GBP", Amount: decimal.FromInt(7021)},
+			b:   MoneyValue{Currency: "EUR", Amount: decimal.FromInt(2500)},
+			err: true,
+		},
+		{
+			a:   MoneyValue{Amount: decimal.FromInt(100)},
+			b:   MoneyValue{Currency: "EUR", Amount: decimal.FromInt(2500)},
+			err: true,
+		},
+	}
+
+	for _, test := range tests {
+		sum, err := test.a.Add(test.b)
+		if test.err {
+			assert.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, 0, test.expected.Amount.Cmp(sum.Amount), sum.Amount.String())
+		assert.Equal(t, test.expected.Currency, sum.Currency)
+	}
+}
+
+func TestMoneyValueAddCard(t *testing.T) {
+	cards := parseTestCardFile(t, "testdata/Lingfield/c20180414lin.xml")
+	var total MoneyValue
+	for _, race := range (*cards)[0].Races {
+		if race.AddedMoney == nil {
+			continue
+		}
+		var err error
+		total, err = total.Add(*race.AddedMoney)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, "GBP", total.Currency)
+	assert.True(t, total.Amount.Cmp(decimal.FromInt(7021)) > 0, total.String())
+}
+
+func TestMoneyValueString(t *testing.T) {
+	tests := []struct {
+		money    MoneyValue
+		expected string
+	}{
+		{money: MoneyValue{Currency: "GBP", Amount: decimal.FromInt(3752)}, expected: "£3752"},
+		{money: MoneyValue{Currency: "EUR", Amount: decimal.New(125050, -2)}, expected: "€1250.50"},
+		{money: MoneyValue{Currency: "HKD", Amount: decimal.FromInt(500)}, expected: "HKD 500"},
+		{money: MoneyValue{Amount: decimal.FromInt(500)}, expected: "500"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.money.String())
+	}
+}
@@ -0,0 +1,570 @@
+package horses
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/advbet/decimal"
+)
+
+// HorseByID returns horse of the race having the given ID.
+func (r *Race) HorseByID(id int) (*Horse, bool) {
+	for i := range r.Horses {
+		if r.Horses[i].ID == id {
+			return &r.Horses[i], true
+		}
+	}
+	return nil, false
+}
+
+// WinnerWasFavourite returns true if the race winner was the (joint) starting
+// price favourite. False is returned if the race has no winner yet.
+func (r *Race) WinnerWasFavourite() bool {
+	for _, h := range r.Horses {
+		if h.isWinner() && h.StartingPrice.FavouritePosition == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// hasWinner returns true if at least one horse in the race has won it.
+func (r *Race) hasWinner() bool {
+	for _, h := range r.Horses {
+		if h.isWinner() {
+			return true
+		}
+	}
+	return false
+}
+
+// isWinner returns true if horse won the race. Amended position takes
+// precedence over the first past the post position.
+func (h *Horse) isWinner() bool {
+	if h.Result == nil {
+		return false
+	}
+	if h.Result.AmendedPos != 0 {
+		return h.Result.AmendedPos == 1
+	}
+	return h.Result.FinishPos == 1 && !h.Result.Disqualified
+}
+
+// Winner returns the race winner. Amended position takes precedence over the
+// first past the post position, disqualified horses do not win. The first
+// horse is returned if horses dead-heated for the win, use Winners to get all
+// of them. False is returned if the race has no winner yet.
+func (r *Race) Winner() (*Horse, bool) {
+	order := r.FinishingOrder()
+	if len(order) == 0 || !order[0].isWinner() {
+		return nil, false
+	}
+	return order[0], true
+}
+
+// Winners returns all the race winners, more than one horse is returned if
+// horses dead-heated for the win. Nil is returned if the race has no winner
+// yet.
+func (r *Race) Winners() []*Horse {
+	var winners []*Horse
+	for _, h := range r.FinishingOrder() {
+		if !h.isWinner() {
+			break
+		}
+		winners = append(winners, h)
+	}
+	return winners
+}
+
+// DeadHeats returns groups of horses sharing a finishing position, in order of
+// the position. Amended position takes precedence over the first past the
+// post position, disqualified horses are not grouped. The first group holds
+// all the co-winners if horses dead-heated for the win. Nil is returned if
+// there were no dead heats.
+func (r *Race) DeadHeats() [][]*Horse {
+	var groups [][]*Horse
+	var group []*Horse
+	for _, h := range r.FinishingOrder() {
+		if h.Result.Disqualified {
+			break
+		}
+		if len(group) > 0 && group[0].position() != h.position() {
+			if len(group) > 1 {
+				groups = append(groups, group)
+			}
+			group = nil
+		}
+		group = append(group, h)
+	}
+	if len(group) > 1 {
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// FinishingOrder returns horses that completed the course sorted by finishing
+// position. Amended position takes precedence over the first past the post
+// position. Disqualified horses are returned at the end of the order.
+func (r *Race) FinishingOrder() []*Horse {
+	var placed, disqualified []*Horse
+	for i := range r.Horses {
+		h := &r.Horses[i]
+		switch {
+		case h.position() == 0:
+		case h.Result.Disqualified:
+			disqualified = append(disqualified, h)
+		default:
+			placed = append(placed, h)
+		}
+	}
+	byPosition := func(horses []*Horse) {
+		sort.SliceStable(horses, func(i, j int) bool {
+			return horses[i].position() < horses[j].position()
+		})
+	}
+	byPosition(placed)
+	byPosition(disqualified)
+	return append(placed, disqualified...)
+}
+
+// OfficialOrder returns the settled finishing order of the race. Horses are
+// sorted by the amended position if stewards amended the result, otherwise by
+// the first past the post position. Disqualified horses are placed at their
+// amended position if stewards demoted them, otherwise they are left out of
+// the order.
+func (r *Race) OfficialOrder() []*Horse {
+	var order []*Horse
+	for _, h := range r.FinishingOrder() {
+		if h.Result.Disqualified && h.Result.AmendedPos == 0 {
+			continue
+		}
+		order = append(order, h)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return order[i].position() < order[j].position()
+	})
+	return order
+}
+
+// position returns finishing position of the horse, amended position takes
+// precedence over the first past the post position. Zero is returned if horse
+// has no result.
+func (h *Horse) position() int {
+	if h.Result == nil {
+		return 0
+	}
+	if h.Result.AmendedPos != 0 {
+		return h.Result.AmendedPos
+	}
+	return h.Result.FinishPos
+}
+
+// Finished returns true if horse completed the course, that is it has a result
+// and no casualty reason. False is returned for horses that did not finish and
+// for races having no result yet.
+func (h *Horse) Finished() bool {
+	return h.Result != nil && h.CasualtyReason == NoCasualty
+}
+
+// FinishPosition returns finishing position of the horse and whether the horse
+// did not finish the race (fell, pulled up etc.). Amended position takes
+// precedence over the first past the post position. Disqualified horse not
+// placed by stewards is returned as not placed, use Result to tell
+// disqualification apart.
+func (h *Horse) FinishPosition() (int, bool) {
+	if h.CasualtyReason != NoCasualty {
+		return 0, true
+	}
+	if h.Result == nil || (h.Result.Disqualified && h.Result.AmendedPos == 0) {
+		return 0, false
+	}
+	return h.position(), false
+}
+
+// IsFinalResult returns true if the race message holds the final settled
+// result, that is the result is official after the weigh in. Earlier result
+// messages may still be amended. Racing file names do not tell final results
+// apart, sequence numbers keep growing after the weigh in.
+func (r *Race) IsFinalResult() bool {
+	return r.Status == RaceWeighedIn
+}
+
+// BeatenFavourites returns a list of races having a result where the starting
+// price favourite did not win.
+func (r *RacingFile) BeatenFavourites() []*Race {
+	var races []*Race
+	for i := range r.Meetings {
+		for j := range r.Meetings[i].Races {
+			race := &r.Meetings[i].Races[j]
+			if race.hasWinner() && !race.WinnerWasFavourite() {
+				races = append(races, race)
+			}
+		}
+	}
+	return races
+}
+
+// FavouriteExcludingNonRunners returns the horse with the shortest price
+// among horses still running in the race. Withdrawn, non runner and reserve
+// horses are ignored. Horses are compared by their CurrentPrice. The first of
+// the joint favourites is returned. Nil is returned if none of the runners is
+// priced.
+func (r *Race) FavouriteExcludingNonRunners() *Horse {
+	var fav *Horse
+	var favPrice *big.Rat
+	for i := range r.Horses {
+		h := &r.Horses[i]
+		if h.Status != HorseRunner {
+			continue
+		}
+		p, ok := h.CurrentPrice()
+		if !ok || p.Sign() <= 0 {
+			continue
+		}
+		if fav == nil || p.Cmp(favPrice) < 0 {
+			fav, favPrice = h, p
+		}
+	}
+	return fav
+}
+
+// SPOverround returns the sum of implied probabilities of starting prices of
+// the runners. Value above 1 is the bookmaker margin, e.g. 1.15 is 15%
+// overround. Withdrawn, non runner and reserve horses are skipped. False is
+// returned if no runner has a starting price.
+func (r *Race) SPOverround() (float64, bool) {
+	var sum float64
+	priced := 0
+	for _, h := range r.Horses {
+		if h.Status != HorseRunner || h.StartingPrice.Price.Sign() <= 0 {
+			continue
+		}
+		odds := new(big.Rat).Add(&h.StartingPrice.Price, big.NewRat(1, 1))
+		prob, _ := new(big.Rat).Inv(odds).Float64()
+		sum += prob
+		priced++
+	}
+	return sum, priced > 0
+}
+
+// Favourite returns the starting price favourite of the race. The first of the
+// joint favourites is returned. False is returned if starting prices are not
+// known yet.
+func (r *Race) Favourite() (*Horse, bool) {
+	for i := range r.Horses {
+		if r.Horses[i].StartingPrice.FavouritePosition == 1 {
+			return &r.Horses[i], true
+		}
+	}
+	return nil, false
+}
+
+// CurrentPrice returns starting price of the horse. If starting price is not
+// known yet the latest offered show of the first betting market is used
+// instead, see LatestShow. False is returned if horse has no price.
+func (h *Horse) CurrentPrice() (*big.Rat, bool) {
+	if h.StartingPrice.Price.Sign() != 0 {
+		return &h.StartingPrice.Price, true
+	}
+	if s, ok := h.LatestShow(1); ok {
+		return &s.Price, true
+	}
+	return nil, false
+}
+
+// Market returns the number of betting market the show is applicable to. Show
+// without market number (market number zero) belongs to the first market.
+func (s Show) Market() int {
+	if s.MarketNumber == 0 {
+		return 1
+	}
+	return s.MarketNumber
+}
+
+// LatestShow returns the most recent show by timestamp in the given betting
+// market. Market number zero is treated as the first market, so are shows
+// having no market number. Shows having no offers are skipped. If several
+// shows have the same timestamp the last one in feed order is returned.
+func (h *Horse) LatestShow(marketNumber int) (*Show, bool) {
+	if marketNumber == 0 {
+		marketNumber = 1
+	}
+	var latest *Show
+	for i := range h.Shows {
+		s := &h.Shows[i]
+		if s.Market() != marketNumber || s.NoOffers {
+			continue
+		}
+		if latest == nil || !s.Timestamp.Before(latest.Timestamp) {
+			latest = s
+		}
+	}
+	return latest, latest != nil
+}
+
+// PricePoint is a single price of a horse price history.
+type PricePoint struct {
+	Timestamp     time.Time // The time of the show, zero for the starting price
+	Price         big.Rat   // The price offered
+	StartingPrice bool      // Whether the price is the starting price
+}
+
+// PriceHistory returns offered show prices of the horse in the given betting
+// market in chronological order. Market number zero is treated as the first
+// market, so are shows having no market number. Shows with no offers are
+// skipped. Starting price, if known, is added as the final point of the latest
+// market the horse has shows in (or of any market if horse has no shows), its
+// timestamp is zero as the feed does not tell when the price was returned.
+func (h *Horse) PriceHistory(marketNumber int) []PricePoint {
+	if marketNumber == 0 {
+		marketNumber = 1
+	}
+	var points []PricePoint
+	lastMarket := 0
+	for i := range h.Shows {
+		s := &h.Shows[i]
+		if s.Market() > lastMarket {
+			lastMarket = s.Market()
+		}
+		if s.Market() != marketNumber || s.NoOffers {
+			continue
+		}
+		points = append(points, PricePoint{Timestamp: s.Timestamp, Price: s.Price})
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Timestamp.Before(points[j].Timestamp)
+	})
+	if h.StartingPrice.Price.Sign() != 0 && (lastMarket == 0 || lastMarket == marketNumber) {
+		points = append(points, PricePoint{Price: h.StartingPrice.Price, StartingPrice: true})
+	}
+	return points
+}
+
+// TimeWindow returns the earliest and the latest scheduled race start times in
+// the document. Zero times are returned if document has no races.
+func (r *RacingFile) TimeWindow() (first, last time.Time) {
+	for _, m := range r.Meetings {
+		for _, race := range m.Races {
+			if first.IsZero() || race.StartTime.Before(first) {
+				first = race.StartTime
+			}
+			if last.IsZero() || race.StartTime.After(last) {
+				last = race.StartTime
+			}
+		}
+	}
+	return first, last
+}
+
+// TrifectaDividend returns tote trifecta dividend of the race together with
+// the winning combination of horse IDs in finishing order. False is returned
+// if race has no trifecta or the dividend is not declared yet.
+func (r *Race) TrifectaDividend() (decimal.Number, []int, bool) {
+	if !r.Trifecta || r.Returns == nil {
+		return decimal.Number{}, nil, false
+	}
+	for _, t := range r.Returns.Tote {
+		if t.Type != ToteTrifecta {
+			continue
+		}
+		var combination []int
+		for _, h := range t.HorseRef {
+			combination = append(combination, h.ID)
+		}
+		return t.Dividend, combination, true
+	}
+	return decimal.Number{}, nil, false
+}
+
+// payoutExp is the exponent of tote payouts scaled from a unit stake other than
+// one, payouts are rounded down to pence.
+const payoutExp = -2
+
+// Payout returns the amount paid by the tote dividend for the given stake. The
+// dividend is paid to the unit Stake, it is scaled by the stake to unit stake
+// ratio. Payouts for unit stakes other than one are rounded down to pence.
+func (t *Tote) Payout(stake decimal.Number) decimal.Number {
+	if t.Stake <= 1 {
+		return t.Dividend.Mul(stake)
+	}
+	payout := new(big.Rat).Mul(t.Dividend.Rat(), stake.Rat())
+	payout.Quo(payout, big.NewRat(int64(t.Stake), 1))
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(-payoutExp), nil)
+	num := new(big.Int).Mul(payout.Num(), scale)
+	num.Quo(num, payout.Denom())
+	return decimal.New(num.Int64(), payoutExp)
+}
+
+// ToteDividend returns the tote dividend of the given type paid for the given
+// horse IDs. Horses are matched in order except for the unordered Quinella and
+// Swinger dividends. Place dividends are declared for every placed horse
+// separately, each of them is looked up by a single horse ID. False is
+// returned if no such dividend is declared.
+func (r *Returns) ToteDividend(t ToteType, horses ...int) (decimal.Number, bool) {
+	for _, tote := range r.Tote {
+		if tote.Type != t || len(tote.HorseRef) != len(horses) {
+			continue
+		}
+		if tote.matches(horses) {
+			return tote.Dividend, true
+		}
+	}
+	return decimal.Number{}, false
+}
+
+// WinDividend returns the tote win dividend. The first dividend is returned if
+// horses dead-heated for the win, use ToteDividend to look up dividend of each
+// winner. False is returned if no win dividend is declared.
+func (r *Returns) WinDividend() (decimal.Number, bool) {
+	for _, tote := range r.Tote {
+		if tote.Type == ToteWin {
+			return tote.Dividend, true
+		}
+	}
+	return decimal.Number{}, false
+}
+
+// Currencies returns currencies of tote and bet dividends in order of first
+// appearance. Dividends not specifying currency are skipped.
+func (r *Returns) Currencies() []string {
+	var currencies []string
+	seen := make(map[string]bool)
+	add := func(currency string) {
+		if currency != "" && !seen[currency] {
+			seen[currency] = true
+			currencies = append(currencies, currency)
+		}
+	}
+	for _, t := range r.Tote {
+		add(t.Currency)
+	}
+	for _, b := range r.Bet {
+		add(b.Currency)
+	}
+	return currencies
+}
+
+// matches returns true if the dividend is paid for the given horse IDs.
+func (t *Tote) matches(horses []int) bool {
+	unordered := t.Type == ToteQuinella || t.Type == ToteSwinger
+	for i, h := range t.HorseRef {
+		if !unordered {
+			if h.ID != horses[i] {
+				return false
+			}
+			continue
+		}
+		found := false
+		for _, id := range horses {
+			if h.ID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Deductions returns Rule Four deductions applied to the betting market. The
+// feed reports only the total deduction of the market, it is attributed to the
+// last withdrawn horse and earlier withdrawals get zero pence, so the sum of
+// returned deductions always equals Deduction. Horse is left empty if the
+// market has a deduction but no withdrawn horse is known. Nil is returned if
+// market has no deduction.
+func (m *BetMarket) Deductions() []Deduction {
+	if m.Deduction == 0 {
+		return nil
+	}
+	if len(m.Withdrawn) == 0 {
+		return []Deduction{{Pence: m.Deduction}}
+	}
+	deductions := make([]Deduction, len(m.Withdrawn))
+	for i, h := range m.Withdrawn {
+		deductions[i].Horse = h
+	}
+	deductions[len(deductions)-1].Pence = m.Deduction
+	return deductions
+}
+
+// ApplyRule4 returns winnings reduced by the given Rule Four deductions. The
+// deductions are summed, the total is capped at 100 pence in the pound. It is
+// up to the caller to check DeductionType of the market the bet was struck in.
+func ApplyRule4(winnings decimal.Number, deductions []Deduction) decimal.Number {
+	total := 0
+	for _, d := range deductions {
+		total += d.Pence
+	}
+	if total <= 0 {
+		return winnings
+	}
+	if total > 100 {
+		total = 100
+	}
+	return winnings.Mul(decimal.New(int64(100-total), -2))
+}
+
+// BetMovements parses BetMovementsComment into the opening ("op") price and
+// touched ("tchd") prices, e.g. "op 5/4 tchd 7/4" is parsed to 5/4 opening and
+// 7/4 touched prices. Only the first betting market is parsed if comment
+// describes several markets ("Mkt1: ...; Mkt2 ..."). Nil opening price is
+// returned if comment has no opening price.
+func (h *Horse) BetMovements() (opening *big.Rat, touched []big.Rat, err error) {
+	market := strings.SplitN(h.BetMovementsComment, ";", 2)[0]
+	fields := strings.Fields(strings.Replace(market, ",", " ", -1))
+
+	inTouched := false
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "op":
+			inTouched = false
+			if i+1 == len(fields) {
+				return nil, nil, fmt.Errorf("parsing bet movements %q: missing opening price", h.BetMovementsComment)
+			}
+			i++
+			p, ok := parseCommentPrice(fields[i])
+			if !ok {
+				return nil, nil, fmt.Errorf("parsing bet movements %q: invalid opening price %q", h.BetMovementsComment, fields[i])
+			}
+			if opening == nil {
+				opening = p
+			}
+		case "tchd":
+			inTouched = true
+		case "and":
+		default:
+			p, ok := parseCommentPrice(fields[i])
+			if !ok {
+				inTouched = false
+				continue
+			}
+			if !inTouched {
+				return nil, nil, fmt.Errorf("parsing bet movements %q: unexpected price %q", h.BetMovementsComment, fields[i])
+			}
+			touched = append(touched, *p)
+		}
+	}
+	return opening, touched, nil
+}
+
+// parseCommentPrice parses fractional price used in comments, e.g. "11/4".
+// Evens are written as "evs" or "Evens".
+func parseCommentPrice(s string) (*big.Rat, bool) {
+	switch strings.ToLower(s) {
+	case "evs", "evens":
+		return big.NewRat(1, 1), true
+	}
+	if !strings.Contains(s, "/") {
+		return nil, false
+	}
+	p, ok := new(big.Rat).SetString(s)
+	if !ok || p.Sign() <= 0 {
+		return nil, false
+	}
+	return p, true
+}
@@ -0,0 +1,738 @@
+package horses
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/advbet/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseTestFile(t *testing.T, file string) *RacingFile {
+	blob, err := ioutil.ReadFile(file)
+	require.NoError(t, err, file)
+	obj, err := ParseRacingFile(blob)
+	require.NoError(t, err, file)
+	return obj
+}
+
+func TestBeatenFavourites(t *testing.T) {
+	tests := []struct {
+		file    string
+		beaten  []int
+		favWins bool
+	}{
+		{
+			// favourite Alexanderthegreat unseated rider, won by Fabianski
+			file:    "testdata/feed/b20181128wth12150045.xml",
+			beaten:  []int{854412},
+			favWins: false,
+		},
+		{
+			// race not yet run
+			file:    "testdata/Lingfield/b20180414lin17400007.xml",
+			beaten:  nil,
+			favWins: false,
+		},
+	}
+
+	for _, test := range tests {
+		obj := parseTestFile(t, test.file)
+		var beaten []int
+		for _, r := range obj.BeatenFavourites() {
+			beaten = append(beaten, r.ID)
+		}
+		assert.Equal(t, test.beaten, beaten, test.file)
+		assert.Equal(t, test.favWins, obj.Meetings[0].Races[0].WinnerWasFavourite(), test.file)
+	}
+}
+
+func TestRaceFavouriteExcludingNonRunners(t *testing.T) {
+	file := "testdata/VaalLateWithdrawal/b20180405vaa14250035.xml"
+	race := testRace(t, file)
+	fav := race.FavouriteExcludingNonRunners()
+	require.NotNil(t, fav)
+	assert.Equal(t, "Angelic", fav.Name)
+
+	// withdrawn Sim-alley Bank shortened to be the market leader
+	race = testRace(t, file)
+	var withdrawn *Horse
+	for i := range race.Horses {
+		if race.Horses[i].Name == "Sim-alley Bank" {
+			withdrawn = &race.Horses[i]
+		}
+	}
+	require.NotNil(t, withdrawn)
+	require.Equal(t, HorseWithdrawn, withdrawn.Status)
+	last := withdrawn.Shows[len(withdrawn.Shows)-1]
+	withdrawn.Shows = append(withdrawn.Shows, Show{Timestamp: last.Timestamp.Add(time.Minute), MarketNumber: 1, Price: makeRat(t, "2/1")})
+	fav = race.FavouriteExcludingNonRunners()
+	require.NotNil(t, fav)
+	assert.Equal(t, "Angelic", fav.Name)
+
+	withdrawn.Status = HorseRunner
+	fav = race.FavouriteExcludingNonRunners()
+	require.NotNil(t, fav)
+	assert.Equal(t, "Sim-alley Bank", fav.Name)
+
+	assert.Nil(t, (&Race{}).FavouriteExcludingNonRunners())
+}
+
+func TestRaceIsFinalResult(t *testing.T) {
+	tests := []struct {
+		file     string
+		expected bool
+	}{
+		{
+			file:     "testdata/WindsorRule4BoardPrices/b20180416wnd16200010.xml",
+			expected: false,
+		},
+		{
+			file:     "testdata/WindsorRule4BoardPrices/b20180416wnd16200082.xml",
+			expected: false,
+		},
+		{
+			file:     "testdata/WindsorRule4BoardPrices/b20180416wnd16200083.xml",
+			expected: true,
+		},
+		{
+			file:     "testdata/WindsorRule4BoardPrices/b20180416wnd16200085.xml",
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		race := testRace(t, test.file)
+		assert.Equal(t, test.expected, race.IsFinalResult(), test.file)
+	}
+}
+
+func TestRacingFileTimeWindow(t *testing.T) {
+	obj := parseTestFile(t, "testdata/Lingfield/b20180414lin17400007.xml")
+	first, last := obj.TimeWindow()
+	assert.WithinDuration(t, makeTime(t, "2018-04-14T17:40:00+01:00"), first, 0)
+	assert.WithinDuration(t, first, last, 0)
+
+	// Multi meeting and multi race document
+	early := parseTestFile(t, "testdata/feed/b20181128wth12150045.xml")
+	obj.Meetings = append(obj.Meetings, early.Meetings...)
+	obj.Meetings[0].Races = append(obj.Meetings[0].Races, Race{StartTime: makeTime(t, "2018-04-14T20:15:00+01:00")})
+	first, last = obj.TimeWindow()
+	assert.WithinDuration(t, makeTime(t, "2018-04-14T17:40:00+01:00"), first, 0)
+	assert.WithinDuration(t, makeTime(t, "2018-11-28T12:15:00Z"), last, 0)
+
+	first, last = (&RacingFile{}).TimeWindow()
+	assert.True(t, first.IsZero())
+	assert.True(t, last.IsZero())
+}
+
+func TestRaceTrifectaDividend(t *testing.T) {
+	obj := parseTestFile(t, "testdata/feed/b20181128wth12150045.xml")
+	dividend, combination, ok := obj.Meetings[0].Races[0].TrifectaDividend()
+	assert.True(t, ok)
+	assert.Equal(t, "336.50", dividend.String())
+	assert.Equal(t, []int{2338916, 2342636, 2298615}, combination)
+
+	// Result is not known yet
+	obj = parseTestFile(t, "testdata/Lingfield/b20180414lin17400007.xml")
+	_, combination, ok = obj.Meetings[0].Races[0].TrifectaDividend()
+	assert.False(t, ok)
+	assert.Nil(t, combination)
+}
+
+func TestHorseBetMovements(t *testing.T) {
+	tests := []struct {
+		comment string
+		opening string
+		touched []string
+		err     bool
+	}{
+		{
+			comment: "op 5/4 tchd 7/4",
+			opening: "5/4",
+			touched: []string{"7/4"},
+		},
+		{
+			comment: "op 25/1",
+			opening: "25/1",
+		},
+		{
+			comment: "tchd 9/2 in places",
+			touched: []string{"9/2"},
+		},
+		{
+			comment: "tchd 5/1, 6/1 in a place",
+			touched: []string{"5/1", "6/1"},
+		},
+		{
+			comment: "op 11/8 tchd 9/4 and tchd 5/2",
+			opening: "11/8",
+			touched: []string{"9/4", "5/2"},
+		},
+		{
+			comment: "Mkt1: op 6/5 tchd 11/10 and tchd 5/4; Mkt2 op 11/10",
+			opening: "6/5",
+			touched: []string{"11/10", "5/4"},
+		},
+		{
+			comment: "op evs tchd 11/10",
+			opening: "1/1",
+			touched: []string{"11/10"},
+		},
+		{
+			comment: "",
+		},
+		{
+			comment: "op",
+			err:     true,
+		},
+		{
+			comment: "op x/y",
+			err:     true,
+		},
+		{
+			comment: "5/4",
+			err:     true,
+		},
+	}
+
+	for _, test := range tests {
+		h := Horse{BetMovementsComment: test.comment}
+		opening, touched, err := h.BetMovements()
+		if test.err {
+			assert.Error(t, err, test.comment)
+			continue
+		}
+		require.NoError(t, err, test.comment)
+		if test.opening == "" {
+			assert.Nil(t, opening, test.comment)
+		} else {
+			require.NotNil(t, opening, test.comment)
+			assert.Equal(t, makeRat(t, test.opening), *opening, test.comment)
+		}
+		require.Len(t, touched, len(test.touched), test.comment)
+		for i, p := range test.touched {
+			assert.Equal(t, makeRat(t, p), touched[i], test.comment)
+		}
+	}
+}
+
+func TestHorseBetMovementsFeed(t *testing.T) {
+	obj := parseTestFile(t, "testdata/feed/b20181128wth12150045.xml")
+	for _, m := range obj.Meetings {
+		for _, r := range m.Races {
+			for _, h := range r.Horses {
+				_, _, err := h.BetMovements()
+				assert.NoError(t, err, h.BetMovementsComment)
+			}
+		}
+	}
+}
+
+func TestRaceFinishingOrder(t *testing.T) {
+	tests := []struct {
+		file   string
+		winner string
+		order  []string
+	}{
+		{
+			// Applause A Star disqualified
+			file:   "testdata/feed/b20181201twm08200023.xml",
+			winner: "Okanagan Miss",
+		},
+		{
+			// Musical Comedy first past the post but disqualified
+			file:   "testdata/EdgeCases/b20131011yor14000026.xml",
+			winner: "Aeolus",
+			order:  []string{"Aeolus", "Sherston", "Penina", "Musical Comedy"},
+		},
+		{
+			file:   "testdata/feed/b20181128wth12150045.xml",
+			winner: "Fabianski",
+			order:  []string{"Fabianski", "Alliteration", "Pepper Street", "Keynote", "Sweet Marmalade", "Astrofire"},
+		},
+	}
+
+	for _, test := range tests {
+		race := testRace(t, test.file)
+		w, ok := race.Winner()
+		require.True(t, ok, test.file)
+		assert.Equal(t, test.winner, w.Name, test.file)
+
+		order := race.FinishingOrder()
+		var names []string
+		for _, h := range order {
+			names = append(names, h.Name)
+		}
+		if test.order != nil {
+			assert.Equal(t, test.order, names, test.file)
+		}
+		last := order[len(order)-1]
+		if last.Result.Disqualified {
+			for _, h := range order[:len(order)-1] {
+				assert.False(t, h.Result.Disqualified, test.file)
+			}
+		}
+	}
+
+	_, ok := (&Race{}).Winner()
+	assert.False(t, ok)
+}
+
+func TestRaceHorseByID(t *testing.T) {
+	race := testRace(t, "testdata/feed/b20181128wth12150045.xml")
+	h, ok := race.HorseByID(2338916)
+	require.True(t, ok)
+	assert.Equal(t, "Fabianski", h.Name)
+
+	_, ok = race.HorseByID(-1)
+	assert.False(t, ok)
+}
+
+func TestBetMarketDeductions(t *testing.T) {
+	tests := []struct {
+		file       string
+		market     int
+		deductions []Deduction
+	}{
+		{
+			file:   "testdata/NewcastleRule4AllBets/b20180419ncs17400061.xml",
+			market: 0,
+			deductions: []Deduction{
+				{Horse: HorseRef{ID: 2156757, Name: "Jassas", Bred: "FR"}, Pence: 20},
+			},
+		},
+		{
+			file:   "testdata/WindsorRule4BoardPrices/b20180416wnd16200085.xml",
+			market: 0,
+			deductions: []Deduction{
+				{Horse: HorseRef{ID: 2211565, Name: "Nightingale Valley", Bred: "GB"}, Pence: 5},
+			},
+		},
+		{
+			file:       "testdata/WindsorRule4BoardPrices/b20180416wnd16200085.xml",
+			market:     1,
+			deductions: nil,
+		},
+	}
+
+	for _, test := range tests {
+		race := testRace(t, test.file)
+		require.Greater(t, len(race.BetMarkets), test.market, test.file)
+		assert.Equal(t, test.deductions, race.BetMarkets[test.market].Deductions(), test.file)
+	}
+}
+
+func TestBetMarketDeductionsSeveral(t *testing.T) {
+	first := HorseRef{ID: 1, Name: "First"}
+	second := HorseRef{ID: 2, Name: "Second"}
+	m := BetMarket{Deduction: 35, Withdrawn: []HorseRef{first, second}}
+	assert.Equal(t, []Deduction{
+		{Horse: first},
+		{Horse: second, Pence: 35},
+	}, m.Deductions())
+
+	m = BetMarket{Deduction: 10}
+	assert.Equal(t, []Deduction{{Pence: 10}}, m.Deductions())
+}
+
+func TestApplyRule4(t *testing.T) {
+	newcastle := testRace(t, "testdata/NewcastleRule4AllBets/b20180419ncs17400061.xml")
+	windsor := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200085.xml")
+	tests := []struct {
+		winnings   string
+		deductions []Deduction
+		expected   string
+	}{
+		{"40.00", newcastle.BetMarkets[0].Deductions(), "32.00"},
+		{"14.00", windsor.BetMarkets[0].Deductions(), "13.30"},
+		{"14.00", nil, "14.00"},
+		{"10.00", []Deduction{{Pence: 60}, {Pence: 60}}, "0.00"},
+	}
+
+	for _, test := range tests {
+		winnings, err := decimal.FromString(test.winnings)
+		require.NoError(t, err)
+		expected, err := decimal.FromString(test.expected)
+		require.NoError(t, err)
+		actual := ApplyRule4(winnings, test.deductions)
+		assert.Equal(t, 0, expected.Cmp(actual), "expected %s, got %s", expected, actual)
+	}
+}
+
+func TestReturnsToteDividend(t *testing.T) {
+	race := testRace(t, "testdata/feed/b20181128wth12150045.xml")
+	require.NotNil(t, race.Returns)
+
+	const (
+		fabianski    = 2338916
+		alliteration = 2342636
+		pepperStreet = 2298615
+	)
+	tests := []struct {
+		typ      ToteType
+		horses   []int
+		dividend string
+		ok       bool
+	}{
+		{ToteWin, []int{fabianski}, "19.20", true},
+		{ToteWin, []int{alliteration}, "", false},
+		{TotePlace, []int{fabianski}, "3.70", true},
+		{TotePlace, []int{alliteration}, "1.60", true},
+		{TotePlace, []int{pepperStreet}, "1.20", true},
+		{ToteExacta, []int{fabianski, alliteration}, "137.70", true},
+		{ToteExacta, []int{alliteration, fabianski}, "", false},
+		{ToteTrifecta, []int{fabianski, alliteration, pepperStreet}, "336.50", true},
+		{ToteSwinger, []int{fabianski, alliteration}, "4.10", true},
+		{ToteSwinger, []int{pepperStreet, alliteration}, "2.00", true},
+		{ToteSwinger, []int{fabianski}, "", false},
+	}
+
+	for _, test := range tests {
+		dividend, ok := race.Returns.ToteDividend(test.typ, test.horses...)
+		assert.Equal(t, test.ok, ok, "%s %v", test.typ, test.horses)
+		if test.ok {
+			expected, err := decimal.FromString(test.dividend)
+			require.NoError(t, err)
+			assert.Equal(t, 0, expected.Cmp(dividend), "%s %v: %s", test.typ, test.horses, dividend)
+		}
+	}
+}
+
+func TestReturnsWinDividend(t *testing.T) {
+	race := testRace(t, "testdata/feed/b20181128wth12150045.xml")
+	require.NotNil(t, race.Returns)
+	dividend, ok := race.Returns.WinDividend()
+	require.True(t, ok)
+	assert.Equal(t, "19.20", dividend.String())
+	assert.Equal(t, []string{"GBP"}, race.Returns.Currencies())
+
+	_, ok = (&Returns{Tote: []Tote{{Type: TotePlace}}}).WinDividend()
+	assert.False(t, ok)
+}
+
+func TestReturnsCurrencies(t *testing.T) {
+	returns := Returns{
+		Tote: []Tote{
+			{Type: ToteWin, Currency: "EUR"},
+			{Type: TotePlace},
+			{Type: TotePlace, Currency: "EUR"},
+		},
+		Bet: []Bet{{Type: BetTypeCSF, Currency: "GBP"}},
+	}
+	assert.Equal(t, []string{"EUR", "GBP"}, returns.Currencies())
+	assert.Empty(t, (&Returns{}).Currencies())
+}
+
+func TestTotePayout(t *testing.T) {
+	tests := []struct {
+		dividend string
+		unit     int
+		stake    string
+		payout   string
+	}{
+		{"19.20", 1, "5", "96.00"},
+		{"3.70", 1, "0.50", "1.85"},
+		{"3.70", 0, "2", "7.40"},
+		{"12.00", 2, "5", "30.00"},
+		{"10.00", 3, "1", "3.33"},
+	}
+
+	for _, test := range tests {
+		dividend, err := decimal.FromString(test.dividend)
+		require.NoError(t, err)
+		stake, err := decimal.FromString(test.stake)
+		require.NoError(t, err)
+		expected, err := decimal.FromString(test.payout)
+		require.NoError(t, err)
+		tote := Tote{Dividend: dividend, Stake: test.unit}
+		actual := tote.Payout(stake)
+		assert.Equal(t, 0, expected.Cmp(actual), "expected %s, got %s", expected, actual)
+	}
+}
+
+func TestHorsePriceHistory(t *testing.T) {
+	race := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200085.xml")
+	h, ok := race.HorseByID(1761741)
+	require.True(t, ok)
+
+	assert.Equal(t, []PricePoint{
+		{Timestamp: makeTime(t, "2018-04-16T16:10:07+01:00"), Price: makeRat(t, "25/1")},
+		{Timestamp: makeTime(t, "2018-04-16T16:11:42+01:00"), Price: makeRat(t, "20/1")},
+		{Timestamp: makeTime(t, "2018-04-16T16:19:29+01:00"), Price: makeRat(t, "25/1")},
+	}, h.PriceHistory(1))
+	assert.Equal(t, []PricePoint{
+		{Timestamp: makeTime(t, "2018-04-16T16:24:02+01:00"), Price: makeRat(t, "20/1")},
+		{Timestamp: makeTime(t, "2018-04-16T16:24:38+01:00"), Price: makeRat(t, "25/1")},
+		{Timestamp: makeTime(t, "2018-04-16T16:25:36+01:00"), Price: makeRat(t, "20/1")},
+		{Price: makeRat(t, "20/1"), StartingPrice: true},
+	}, h.PriceHistory(2))
+	assert.Empty(t, h.PriceHistory(3))
+	assert.Equal(t, h.PriceHistory(1), h.PriceHistory(0))
+
+	price, ok := h.CurrentPrice()
+	require.True(t, ok)
+	assert.Equal(t, "20/1", price.String())
+
+	h = &Horse{Shows: []Show{
+		{Timestamp: makeTime(t, "2018-04-16T16:11:00+01:00"), MarketNumber: 1, Price: makeRat(t, "5/1")},
+		{Timestamp: makeTime(t, "2018-04-16T16:12:00+01:00"), MarketNumber: 1, NoOffers: true},
+	}}
+	price, ok = h.CurrentPrice()
+	require.True(t, ok)
+	assert.Equal(t, "5/1", price.String())
+	assert.Len(t, h.PriceHistory(1), 1)
+
+	_, ok = (&Horse{}).CurrentPrice()
+	assert.False(t, ok)
+
+	// shows of later markets are not mixed into the first market price
+	h = &Horse{Shows: []Show{
+		{Timestamp: makeTime(t, "2018-04-16T16:12:00+01:00"), MarketNumber: 1, Price: makeRat(t, "7/2")},
+		{Timestamp: makeTime(t, "2018-04-16T16:11:00+01:00"), MarketNumber: 1, Price: makeRat(t, "4/1")},
+		{Timestamp: makeTime(t, "2018-04-16T16:13:00+01:00"), MarketNumber: 2, Price: makeRat(t, "3/1")},
+	}}
+	price, ok = h.CurrentPrice()
+	require.True(t, ok)
+	assert.Equal(t, "7/2", price.String())
+	assert.Equal(t, "7/2", h.latestPrice())
+	show, ok := h.LatestShow(0)
+	require.True(t, ok)
+	assert.Equal(t, "7/2", show.Price.String())
+	show, ok = h.LatestShow(2)
+	require.True(t, ok)
+	assert.Equal(t, "3/1", show.Price.String())
+	_, ok = h.LatestShow(3)
+	assert.False(t, ok)
+
+	// shows having no market number belong to the first market
+	h = &Horse{Shows: []Show{
+		{Timestamp: makeTime(t, "2018-04-16T16:11:00+01:00"), Price: makeRat(t, "4/1")},
+		{Timestamp: makeTime(t, "2018-04-16T16:12:00+01:00"), MarketNumber: 1, Price: makeRat(t, "7/2")},
+	}, StartingPrice: StartingPrice{Price: makeRat(t, "3/1")}}
+	assert.Len(t, h.PriceHistory(1), 3)
+	assert.Equal(t, h.PriceHistory(1), h.PriceHistory(0))
+}
+
+func TestShowMarket(t *testing.T) {
+	tests := []struct {
+		xml    string
+		number int
+		market int
+	}{
+		{
+			xml:    `<Show timeStamp="20180416T161007+0100" marketNumber="2" noOffers="Yes"/>`,
+			number: 2,
+			market: 2,
+		},
+		{
+			xml:    `<Show timeStamp="20180416T161007+0100" marketNumber="1" noOffers="Yes"/>`,
+			number: 1,
+			market: 1,
+		},
+		{
+			xml:    `<Show timeStamp="20180416T161007+0100" noOffers="Yes"/>`,
+			number: 0,
+			market: 1,
+		},
+	}
+
+	for _, test := range tests {
+		var show xmlShow
+		require.NoError(t, xml.Unmarshal([]byte(test.xml), &show), test.xml)
+		assert.Equal(t, test.number, show.MarketNumber, test.xml)
+		assert.Equal(t, test.market, Show(show).Market(), test.xml)
+	}
+}
+
+func TestRaceSPOverround(t *testing.T) {
+	race := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200085.xml")
+	overround, ok := race.SPOverround()
+	require.True(t, ok)
+	assert.InDelta(t, 1.2024, overround, 0.0001)
+
+	// withdrawn horse price is not included
+	race.Horses = append(race.Horses, Horse{Status: HorseWithdrawn, StartingPrice: StartingPrice{Price: makeRat(t, "1/1")}})
+	withdrawn, ok := race.SPOverround()
+	require.True(t, ok)
+	assert.Equal(t, overround, withdrawn)
+
+	_, ok = (&Race{}).SPOverround()
+	assert.False(t, ok)
+}
+
+func TestRaceFavourite(t *testing.T) {
+	race := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200085.xml")
+	fav, ok := race.Favourite()
+	require.True(t, ok)
+	assert.Equal(t, "Mobsta", fav.Name)
+
+	race = testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200010.xml")
+	_, ok = race.Favourite()
+	assert.False(t, ok)
+}
+
+func TestRaceDeadHeats(t *testing.T) {
+	race := testRace(t, "testdata/Lingfield/b20180414lin14300060.xml")
+	var groups [][]string
+	for _, group := range race.DeadHeats() {
+		var names []string
+		for _, h := range group {
+			names = append(names, h.Name)
+		}
+		groups = append(groups, names)
+	}
+	assert.Equal(t, [][]string{{"The Emperor Within", "The Establishment"}}, groups)
+	require.Len(t, race.Winners(), 1)
+	assert.Equal(t, "Mesquite", race.Winners()[0].Name)
+
+	race = testRace(t, "testdata/feed/b20181128wth12150045.xml")
+	assert.Nil(t, race.DeadHeats())
+
+	// dead heat for the win, disqualified horse is not grouped
+	race = Race{
+		Horses: []Horse{
+			{ID: 1, Result: &Result{FinishPos: 1}},
+			{ID: 2, Result: &Result{FinishPos: 1, BetweenDistance: "Dead Heat"}},
+			{ID: 3, Result: &Result{FinishPos: 3}},
+			{ID: 4, Result: &Result{FinishPos: 3, Disqualified: true}},
+		},
+	}
+	deadHeats := race.DeadHeats()
+	require.Len(t, deadHeats, 1)
+	assert.Equal(t, []*Horse{&race.Horses[0], &race.Horses[1]}, deadHeats[0])
+	assert.Equal(t, []*Horse{&race.Horses[0], &race.Horses[1]}, race.Winners())
+	w, ok := race.Winner()
+	require.True(t, ok)
+	assert.Equal(t, 1, w.ID)
+
+	assert.Nil(t, (&Race{}).Winners())
+}
+
+func TestRaceOfficialOrder(t *testing.T) {
+	tests := []struct {
+		file  string
+		order []string
+	}{
+		{
+			// Applause A Star disqualified and placed last
+			file:  "testdata/feed/b20181201twm08200023.xml",
+			order: []string{"Okanagan Miss", "Mishani Rainman", "Timetus", "Dreamscope", "Applause A Star"},
+		},
+		{
+			// Musical Comedy first past the post but demoted to second
+			file:  "testdata/EdgeCases/b20131011yor14000026.xml",
+			order: []string{"Aeolus", "Musical Comedy", "Sherston", "Penina"},
+		},
+	}
+
+	for _, test := range tests {
+		race := testRace(t, test.file)
+		var names []string
+		for _, h := range race.OfficialOrder() {
+			names = append(names, h.Name)
+		}
+		assert.Equal(t, test.order, names, test.file)
+	}
+
+	// result not amended, official order is the finishing order
+	race := testRace(t, "testdata/GreyvilleJockeyChanges/b20180304gre15150048.xml")
+	order := race.OfficialOrder()
+	assert.Len(t, order, 15)
+	assert.Equal(t, race.FinishingOrder(), order)
+
+	// disqualified horse without amended position is left out
+	race = Race{
+		Horses: []Horse{
+			{ID: 1, Result: &Result{FinishPos: 1, Disqualified: true}},
+			{ID: 2, Result: &Result{FinishPos: 2}},
+		},
+	}
+	assert.Equal(t, []*Horse{&race.Horses[1]}, race.OfficialOrder())
+}
+
+func TestHorseFinishPosition(t *testing.T) {
+	tests := []struct {
+		horse            Horse
+		expectedPlace    int
+		expectedDNF      bool
+		expectedFinished bool
+	}{
+		{
+			horse:            Horse{Result: &Result{FinishPos: 2}},
+			expectedPlace:    2,
+			expectedDNF:      false,
+			expectedFinished: true,
+		},
+		{
+			horse:            Horse{Result: &Result{FinishPos: 1}},
+			expectedPlace:    1,
+			expectedDNF:      false,
+			expectedFinished: true,
+		},
+		{
+			horse:            Horse{CasualtyReason: PulledUp},
+			expectedPlace:    0,
+			expectedDNF:      true,
+			expectedFinished: false,
+		},
+		{
+			horse:            Horse{},
+			expectedPlace:    0,
+			expectedDNF:      false,
+			expectedFinished: false,
+		},
+		{
+			horse:            Horse{Result: &Result{FinishPos: 1, Disqualified: true}},
+			expectedPlace:    0,
+			expectedDNF:      false,
+			expectedFinished: true,
+		},
+		{
+			horse:            Horse{Result: &Result{FinishPos: 1, Disqualified: true, AmendedPos: 3}},
+			expectedPlace:    3,
+			expectedDNF:      false,
+			expectedFinished: true,
+		},
+		{
+			horse:            Horse{Result: &Result{FinishPos: 3, AmendedPos: 2}},
+			expectedPlace:    2,
+			expectedDNF:      false,
+			expectedFinished: true,
+		},
+	}
+
+	for _, test := range tests {
+		place, dnf := test.horse.FinishPosition()
+		assert.Equal(t, test.expectedPlace, place)
+		assert.Equal(t, test.expectedDNF, dnf)
+		assert.Equal(t, test.expectedFinished, test.horse.Finished())
+	}
+}
+
+func TestHorseFinished(t *testing.T) {
+	race := testRace(t, "testdata/feed/b20181128wth12150045.xml")
+
+	finished := make(map[string]bool)
+	for i := range race.Horses {
+		h := &race.Horses[i]
+		_, dnf := h.FinishPosition()
+		assert.NotEqual(t, dnf, h.Finished(), h.Name)
+		finished[h.Name] = h.Finished()
+	}
+	assert.Equal(t, map[string]bool{
+		"Alexanderthegreat": false,
+		"Alliteration":      true,
+		"Burnieboozle":      false,
+		"Keynote":           true,
+		"Astrofire":         true,
+		"Don't Fence Me In": false,
+		"Fabianski":         true,
+		"Kheleyf's Girl":    false,
+		"Pepper Street":     true,
+		"Sweet Marmalade":   true,
+	}, finished)
+}
@@ -4,6 +4,10 @@ import (
 	"encoding/xml"
 	"fmt"
 	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/advbet/decimal"
@@ -21,10 +25,10 @@ type StewardsStatus string
 // DeductionType is an enum of rule four deduction types.
 type DeductionType string
 
-// ToteType is an enum of tote retrn types.
+// ToteType is an enum of tote retrn types. Unknown types are kept as received.
 type ToteType string
 
-// BetType is an enum of bet return types.
+// BetType is an enum of bet return types. Unknown types are kept as received.
 type BetType string
 
 // HorseStatus describes horse participation in the race.
@@ -63,6 +67,20 @@ type xmlPrice big.Rat
 
 // RacingFile is the main object sent via PA horse racing feed. It holds all
 // the information for a single (or more) horse races.
+//
+// JSON output of RacingFile follows the Go structure of the document, object
+// keys are Go field names. Values are encoded as follows:
+//
+//   - prices (StartingPrice.Price, Show.Price) are fractional strings, e.g.
+//     "13/8" or "1/1" for evens, empty string if price is unknown;
+//   - durations (Race.WinTime) are numbers of seconds, e.g. 243.5, null if
+//     absent;
+//   - times are RFC 3339 strings, zero time is "0001-01-01T00:00:00Z", absent
+//     time (Race.OffTime) is null;
+//   - decimal amounts (dividends, pools, money) are JSON numbers;
+//   - MoneyValue, UnitsValue and UnitsValueText are objects having Currency
+//     and Amount, Units and Value (and Text) fields respectively;
+//   - enum values are their PA feed string values.
 type RacingFile struct {
 	Timestamp time.Time
 	Meetings  []Meeting
@@ -102,14 +120,14 @@ type Race struct {
 	Stewards  StewardsStatus // Indicates that the stewards are involved
 	Status    RaceStatus     // Status of the race
 	//PrizeMoney UNUSED      // Prize money awarded for the race
-	Weather           string        // The weather for this race
-	GoingBrief        string        // Brief description of going e.g. "Good"
-	GoingFull         string        // Full description of going. e.g Good (Good to Soft in places)
-	OffTime           time.Time     // The time at which the race started, or zero if unknown
-	WinTime           time.Duration // The time taken for the winner to complete the course, or zero if unknown
-	StewardsInquiry   string        // Stewards details regarding stewards inquiry
-	StewardsObjection string        // Stewards details regarding objection
-	BetMarkets        []BetMarket   // Betting market information (includes Rule Four)
+	Weather           string         // The weather for this race
+	GoingBrief        string         // Brief description of going e.g. "Good"
+	GoingFull         string         // Full description of going. e.g Good (Good to Soft in places)
+	OffTime           *time.Time     // The time at which the race started, nil if not yet off or unknown
+	WinTime           *time.Duration // The time taken for the winner to complete the course, nil if unknown
+	StewardsInquiry   string         // Stewards details regarding stewards inquiry
+	StewardsObjection string         // Stewards details regarding objection
+	BetMarkets        []BetMarket    // Betting market information (includes Rule Four)
 	//LackFinishers   UNUSED                 // Used if not enough finishers to fill result
 	//Message         UNUSED                 // Any other information about the race
 	Horses []Horse // The horses running in the race
@@ -203,10 +221,31 @@ type BetMarket struct {
 	Suspended     time.Time     // Date/time when market was suspended (format ISO 8601:1988 yyyymmddThhmmss+/-hhmm)
 	Deduction     int           // Amount deducted to this market (Rule Four)
 	DeductionType DeductionType // Type of deduction applied
+	Withdrawn     []HorseRef    // Horses withdrawn from this market, in order of withdrawal
+	Suspensions   []Suspension  // Suspensions of this market in chronological order, accumulated by MergeRace
 }
 
 type xmlBetMarket BetMarket
 
+// Suspension is a single suspension of the betting market. PA does not send
+// suspension reasons, a horse withdrawn from the market shortly before the
+// suspension is reported as its likely cause.
+type Suspension struct {
+	Time      time.Time // Date/time when market was suspended
+	Withdrawn *HorseRef // Horse withdrawn from the market at most suspensionWindow before the suspension, nil if none
+}
+
+// suspensionWindow is the longest delay between a horse withdrawal and the
+// market suspension for the withdrawal to be matched with the suspension.
+const suspensionWindow = time.Minute
+
+// Deduction is a Rule Four deduction caused by a horse withdrawn from a
+// betting market.
+type Deduction struct {
+	Horse HorseRef // The withdrawn horse
+	Pence int      // Deduction in pence in the pound
+}
+
 // Horse is data of a single horse participating in the race.
 type Horse struct {
 	ID          int         // The internal identifier for the horse
@@ -220,11 +259,12 @@ type Horse struct {
 	Trainer            Trainer        // The trainer of the horse
 	Shows              []Show         // The betting show(s) on the horse
 	StartingPrice      StartingPrice  // The starting price of the horse
-	WithdrawnBetMarket int            // The number of the betting market withdrawn from
-	WithdrawnTime      time.Time      // The time of withdrawal (yyyymmddThhmm+/-hhmm)
+	WithdrawnBetMarket int            // The number of the betting market withdrawn from, zero if withdrawn before any market was formed
+	WithdrawnTime      time.Time      // The time of withdrawal (yyyymmddThhmm+/-hhmm), zero if withdrawn before any market was formed
 	//PhotoFinish UNUSED      `xml:"PhotoFinish"`   // Indicates horse involved in a photo-finish
 	Result              *Result        // Result details if horse completed the course
 	CasualtyReason      CasualtyReason // Reason horse failed to complete race. A value of "DidNotFinish" is used when the official reason for failing to complete has not yet been announced.
+	CasualtyText        string         // Raw casualty reason, set only if CasualtyReason is CasualtyUnknown
 	CloseUpComment      string         // Description of how horse ran e.g. "held up in touch, ridden before 3 out, weakened"
 	BetMovementsComment string         // Description of odds availability e.g. "op 11/8 tchd 9/4 in places"
 	//BigBets     TODO // Big bet details
@@ -295,6 +335,15 @@ type xmlMoneyValue struct {
 	Amount   decimal.Number `xml:"amount,attr"`   // Amount of money in a given currency
 }
 
+// WithDefaultCurrency returns a copy of money value with currency set to the
+// given one if money value does not specify its own currency.
+func (m MoneyValue) WithDefaultCurrency(currency string) MoneyValue {
+	if m.Currency == "" {
+		m.Currency = currency
+	}
+	return m
+}
+
 // UnitsValue is a helper struct for storing integer values in weight or
 // length units.
 type UnitsValue struct {
@@ -307,6 +356,10 @@ type xmlUnitsValue struct {
 	Value int    `xml:"value,attr"` // The value in the specified units
 }
 
+// xmlAllowance is same as xmlUnitsValue, but tolerates units and value
+// attributes being swapped.
+type xmlAllowance UnitsValue
+
 // UnitsValueText is same type as UnitsValue but with additional text field
 // which contains human readable textual value in untis representation.
 type UnitsValueText struct {
@@ -409,6 +462,11 @@ const (
 	HitRails      CasualtyReason = "HitRails"
 	RefusedToRace CasualtyReason = "RefusedToRace"
 	DidNotFinish  CasualtyReason = "DidNotFinish"
+	LeftAtStart   CasualtyReason = "LeftAtStart"
+
+	// CasualtyUnknown is used for reasons not listed above, the raw value
+	// is kept in Horse.CasualtyText.
+	CasualtyUnknown CasualtyReason = "Unknown"
 )
 
 // List of allowed SellingDetailsType values.
@@ -501,10 +559,10 @@ func (m *xmlMeeting) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 		Status: MeetingDormant,
 	}
 	if err := d.DecodeElement(&data, &start); err != nil {
-		return err
+		return fmt.Errorf("meeting %d: %w", data.ID, err)
 	}
 	if !data.Status.isValid() {
-		return fmt.Errorf("invalid Meeting status attibute value: %s", data.Status)
+		return fmt.Errorf("meeting %d: invalid Meeting status attibute value: %s", data.ID, data.Status)
 	}
 	var races []Race
 	for _, r := range data.Races {
@@ -549,8 +607,8 @@ func (r *xmlRace) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 			Brief string `xml:"brief,attr"` // Brief description of going e.g. "Good"
 			Full  string `xml:",chardata"`  // Element contains full description of going. e.g Good (Good to Soft in places)
 		} `xml:"Going"` // The going for this race
-		OffTime         xmlTimeElement `xml:"OffTime"` // The time at which the race started
-		WinTime         xmlDuration    `xml:"WinTime"` // The time taken for the winner to complete the course
+		OffTime         *xmlTimeElement `xml:"OffTime"` // The time at which the race started
+		WinTime         *xmlDuration    `xml:"WinTime"` // The time taken for the winner to complete the course
 		StewardsDetails struct {
 			Inquiry struct {
 				Data string `xml:",chardata"`
@@ -594,11 +652,11 @@ func (r *xmlRace) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	}
 
 	if err := d.DecodeElement(&data, &start); err != nil {
-		return err
+		return fmt.Errorf("race %d: %w", data.ID, err)
 	}
 	startTime, err := time.Parse("20060102T1504-0700", fmt.Sprintf("%sT%s", data.Date, data.Time))
 	if err != nil {
-		return fmt.Errorf("parsing Race.date and Race.time: %v", err)
+		return fmt.Errorf("race %d: parsing Race.date and Race.time: %w", data.ID, err)
 	}
 
 	var betMarkets []BetMarket
@@ -609,6 +667,32 @@ func (r *xmlRace) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	for _, h := range data.Horses {
 		horses = append(horses, Horse(h))
 	}
+	withdrawn := make([]Horse, len(horses))
+	copy(withdrawn, horses)
+	sort.SliceStable(withdrawn, func(i, j int) bool {
+		return withdrawn[i].WithdrawnTime.Before(withdrawn[j].WithdrawnTime)
+	})
+	for i := range betMarkets {
+		for _, h := range withdrawn {
+			if h.Status != HorseWithdrawn || h.WithdrawnBetMarket != betMarkets[i].MarketNumber {
+				continue
+			}
+			ref := HorseRef{
+				ID:   h.ID,
+				Name: h.Name,
+				Bred: h.Bred,
+			}
+			betMarkets[i].Withdrawn = append(betMarkets[i].Withdrawn, ref)
+			for j := range betMarkets[i].Suspensions {
+				s := &betMarkets[i].Suspensions[j]
+				delay := s.Time.Sub(h.WithdrawnTime)
+				if s.Withdrawn == nil && delay >= 0 && delay <= suspensionWindow {
+					ref := ref
+					s.Withdrawn = &ref
+				}
+			}
+		}
+	}
 	*r = xmlRace{
 		ID:        data.ID,
 		Revision:  data.Revision,
@@ -623,8 +707,8 @@ func (r *xmlRace) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		Weather:           data.Weather,
 		GoingBrief:        data.Going.Brief,
 		GoingFull:         data.Going.Full,
-		OffTime:           time.Time(data.OffTime),
-		WinTime:           time.Duration(data.WinTime),
+		OffTime:           (*time.Time)(data.OffTime),
+		WinTime:           (*time.Duration)(data.WinTime),
 		StewardsInquiry:   data.StewardsDetails.Inquiry.Data,
 		StewardsObjection: data.StewardsDetails.Objection.Data,
 		BetMarkets:        betMarkets,
@@ -658,69 +742,99 @@ func (m *xmlBetMarket) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 		Deduction:     data.Deduction,
 		DeductionType: data.DeductionType,
 	}
+	if !m.Suspended.IsZero() {
+		m.Suspensions = []Suspension{{Time: m.Suspended}}
+	}
 	return nil
 }
 
+// xmlHorseData is the decoded Horse element. Horses are among the most
+// frequent elements, decoded elements are pooled to reduce allocations, see
+// xmlHorse.UnmarshalXML.
+type xmlHorseData struct {
+	ID     int         `xml:"id,attr"`     // The internal identifier for the horse
+	Name   string      `xml:"name,attr"`   // The name of the horse
+	Bred   string      `xml:"bred,attr"`   // The country of breeding of the horse
+	Status HorseStatus `xml:"status,attr"` // Horse status regarding this race
+	Cloth  struct {
+		Number int `xml:"number,attr"` // Saddlecloth or racecard number of horse
+		// In races where two or more horses have been "coupled" together,
+		// these horses share the same "number" but have an additional letter
+		// to be able to tell them apart. For example 1 and 1a.
+		Coupled string `xml:"coupled,attr"`
+	} `xml:"Cloth"` // The saddlecloth number for the horse
+	Weight        xmlUnitsValueText `xml:"Weight"`        // The weight carried by the horse
+	Jockey        xmlJockey         `xml:"Jockey"`        // The jockey riding the horse
+	Trainer       xmlTrainer        `xml:"Trainer"`       // The trainer of the horse
+	Shows         []xmlShow         `xml:"Show"`          // The betting show(s) on the horse
+	StartingPrice xmlStartingPrice  `xml:"StartingPrice"` // The starting price of the horse
+	Withdrawn     struct {
+		BetMarket     int            `xml:"betMarket,attr"`     // The number of the betting market withdrawn from
+		TimeWithdrawn xmlTimeElement `xml:"timeWithdrawn,attr"` // The time of withdrawal (yyyymmddThhmm+/-hhmm)
+		//Price UNUSED xmlPrice     `xml:"Price"`              // The price of the withdrawn horse (if there was one)
+		//Favourite UNUSED *struct {
+		//	Position int `xml:"position,attr"` // Position in market, 1 = favourite, 2 = 2nd favourite etc.
+		//	Joint    int `xml:"joint,attr"`    // Number sharing this position in market (2 = jt, 3 = co etc)
+		//} `xml:"Favourite"` // Indicates withdrawn favourite (where applicable)
+	} `xml:"Withdrawn"` // Details if horse was withdrawn
+	//PhotoFinish   UNUSED      `xml:"PhotoFinish"`   // Indicates horse involved in a photo-finish
+	Result   *xmlResult `xml:"Result"` // Result details if horse completed the course
+	Casualty struct {
+		Reason CasualtyReason `xml:"reason,attr"` // Reason horse failed to complete race. A value of "DidNotFinish" is used when the official reason for failing to complete has not yet been announced.
+	} `xml:"Casualty"` // Casualty details if the horse did not complete the course
+	CloseUp struct {
+		Comment string `xml:"comment,attr"` // Description of how horse ran e.g. "held up in touch, ridden before 3 out, weakened"
+	} `xml:"CloseUp"` // Closeup comment for the horse
+	BetMovements struct {
+		Comment string `xml:"comment,attr"` // Description of odds availability e.g. "op 11/8 tchd 9/4 in places"
+	} `xml:"BetMovements"` // Details of betting movements
+	BigBets *struct {
+		BigBetCash []struct {
+			Type     string `xml:"type,attr"`     // Type of bet placed
+			Currency string `xml:"currency,attr"` // The currency paid in e.g. GBP
+			Stake    int    `xml:"stake,attr"`    // Size of stake involved
+			Win      int    `xml:"win,attr"`      // What the bet would have won
+			Count    int    `xml:"count,attr"`    // Number of times this bet was placed
+		} `xml:"BigBetCash"` // Cash bets placed on the horse
+		BigBetOffice []struct {
+			Type string `xml:"type,attr"` // Type of office money
+		} `xml:"BigBetOffice"` // Office money details for the horse
+	} `xml:"BigBets"` // Big bet details
+}
+
+var horseDataPool = sync.Pool{New: func() interface{} { return new(xmlHorseData) }}
+
+// reset clears decoded horse keeping capacity of the shows slice.
+func (h *xmlHorseData) reset() {
+	for i := range h.Shows {
+		h.Shows[i] = xmlShow{}
+	}
+	*h = xmlHorseData{Shows: h.Shows[:0]}
+}
+
 // UnmarshalXML implements xml.Unmarshaler interface.
 func (h *xmlHorse) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	data := struct {
-		ID     int         `xml:"id,attr"`     // The internal identifier for the horse
-		Name   string      `xml:"name,attr"`   // The name of the horse
-		Bred   string      `xml:"bred,attr"`   // The country of breeding of the horse
-		Status HorseStatus `xml:"status,attr"` // Horse status regarding this race
-		Cloth  struct {
-			Number int `xml:"number,attr"` // Saddlecloth or racecard number of horse
-			// In races where two or more horses have been "coupled" together,
-			// these horses share the same "number" but have an additional letter
-			// to be able to tell them apart. For example 1 and 1a.
-			Coupled string `xml:"coupled,attr"`
-		} `xml:"Cloth"` // The saddlecloth number for the horse
-		Weight        xmlUnitsValueText `xml:"Weight"`        // The weight carried by the horse
-		Jockey        xmlJockey         `xml:"Jockey"`        // The jockey riding the horse
-		Trainer       xmlTrainer        `xml:"Trainer"`       // The trainer of the horse
-		Shows         []xmlShow         `xml:"Show"`          // The betting show(s) on the horse
-		StartingPrice xmlStartingPrice  `xml:"StartingPrice"` // The starting price of the horse
-		Withdrawn     struct {
-			BetMarket     int            `xml:"betMarket,attr"`     // The number of the betting market withdrawn from
-			TimeWithdrawn xmlTimeElement `xml:"timeWithdrawn,attr"` // The time of withdrawal (yyyymmddThhmm+/-hhmm)
-			//Price UNUSED xmlPrice     `xml:"Price"`              // The price of the withdrawn horse (if there was one)
-			//Favourite UNUSED *struct {
-			//	Position int `xml:"position,attr"` // Position in market, 1 = favourite, 2 = 2nd favourite etc.
-			//	Joint    int `xml:"joint,attr"`    // Number sharing this position in market (2 = jt, 3 = co etc)
-			//} `xml:"Favourite"` // Indicates withdrawn favourite (where applicable)
-		} `xml:"Withdrawn"` // Details if horse was withdrawn
-		//PhotoFinish   UNUSED      `xml:"PhotoFinish"`   // Indicates horse involved in a photo-finish
-		Result   *xmlResult `xml:"Result"` // Result details if horse completed the course
-		Casualty struct {
-			Reason CasualtyReason `xml:"reason,attr"` // Reason horse failed to complete race. A value of "DidNotFinish" is used when the official reason for failing to complete has not yet been announced.
-		} `xml:"Casualty"` // Casualty details if the horse did not complete the course
-		CloseUp struct {
-			Comment string `xml:"comment,attr"` // Description of how horse ran e.g. "held up in touch, ridden before 3 out, weakened"
-		} `xml:"CloseUp"` // Closeup comment for the horse
-		BetMovements struct {
-			Comment string `xml:"comment,attr"` // Description of odds availability e.g. "op 11/8 tchd 9/4 in places"
-		} `xml:"BetMovements"` // Details of betting movements
-		BigBets *struct {
-			BigBetCash []struct {
-				Type     string `xml:"type,attr"`     // Type of bet placed
-				Currency string `xml:"currency,attr"` // The currency paid in e.g. GBP
-				Stake    int    `xml:"stake,attr"`    // Size of stake involved
-				Win      int    `xml:"win,attr"`      // What the bet would have won
-				Count    int    `xml:"count,attr"`    // Number of times this bet was placed
-			} `xml:"BigBetCash"` // Cash bets placed on the horse
-			BigBetOffice []struct {
-				Type string `xml:"type,attr"` // Type of office money
-			} `xml:"BigBetOffice"` // Office money details for the horse
-		} `xml:"BigBets"` // Big bet details
-	}{}
-
-	if err := d.DecodeElement(&data, &start); err != nil {
-		return err
+	data := horseDataPool.Get().(*xmlHorseData)
+	defer func() {
+		data.reset()
+		horseDataPool.Put(data)
+	}()
+
+	if err := d.DecodeElement(data, &start); err != nil {
+		return fmt.Errorf("horse %d: %w", data.ID, err)
 	}
+	casualty, casualtyText := parseCasualtyReason(string(data.Casualty.Reason))
 	var shows []Show
+	if len(data.Shows) > 0 {
+		shows = make([]Show, 0, len(data.Shows))
+	}
 	for _, s := range data.Shows {
 		shows = append(shows, Show(s))
 	}
+	// shows usually arrive in chronological order, but it is not guaranteed
+	sort.SliceStable(shows, func(i, j int) bool {
+		return shows[i].Timestamp.Before(shows[j].Timestamp)
+	})
 	*h = xmlHorse{
 		ID:          data.ID,
 		Name:        data.Name,
@@ -737,7 +851,8 @@ func (h *xmlHorse) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		WithdrawnTime:      time.Time(data.Withdrawn.TimeWithdrawn),
 		//PhotoFinish   UNUSED
 		Result:              (*Result)(data.Result),
-		CasualtyReason:      data.Casualty.Reason,
+		CasualtyReason:      casualty,
+		CasualtyText:        casualtyText,
 		CloseUpComment:      data.CloseUp.Comment,
 		BetMovementsComment: data.BetMovements.Comment,
 		//BigBets TODO
@@ -843,12 +958,11 @@ func (sp *xmlStartingPrice) UnmarshalXML(d *xml.Decoder, start xml.StartElement)
 // UnmarshalXML implements xml.Unmarshaler interface.
 func (j *xmlJockey) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	data := struct {
-		ID            int      `xml:"id,attr"`            // Identifier for jockey
-		Name          string   `xml:"name,attr"`          // The name of the jockey
-		RaceDayChange xmlYesNo `xml:"raceDayChange,attr"` // Flag to indicate whether the jockey has changed on the race day.
-		// Removed due to a bug found in Australian feed
-		// Allowance     xmlUnitsValue `xml:"Allowance"`          // The allowance of the jockey
-		Overweight xmlUnitsValue `xml:"Overweight"` // Overweight information. Present only if the jockey is too heavy
+		ID            int           `xml:"id,attr"`            // Identifier for jockey
+		Name          string        `xml:"name,attr"`          // The name of the jockey
+		RaceDayChange xmlYesNo      `xml:"raceDayChange,attr"` // Flag to indicate whether the jockey has changed on the race day.
+		Allowance     xmlAllowance  `xml:"Allowance"`          // The allowance of the jockey
+		Overweight    xmlUnitsValue `xml:"Overweight"`         // Overweight information. Present only if the jockey is too heavy
 	}{}
 
 	if err := d.DecodeElement(&data, &start); err != nil {
@@ -858,23 +972,63 @@ func (j *xmlJockey) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		ID:            data.ID,
 		Name:          data.Name,
 		RaceDayChange: bool(data.RaceDayChange),
-		// Allowance:     UnitsValue(data.Allowance),
-		Overweight: UnitsValue(data.Overweight),
+		Allowance:     UnitsValue(data.Allowance),
+		Overweight:    UnitsValue(data.Overweight),
 	}
 	return nil
 }
 
-// UnmarshalXML implements xml.Unmarshaler interface.
-func (s *xmlShow) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+// UnmarshalXML implements xml.Unmarshaler interface. Australian feed is known
+// to send allowance with units and value attributes swapped, e.g.
+// units="3" value="lbs", such values are swapped back.
+func (a *xmlAllowance) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	data := struct {
-		Timestamp    xmlTimeElement `xml:"timestamp,attr"`    // The time at which the show was recieved (format ISO 8601:1988 yyyymmddThhmmss+/-hhmm). Acts as a unique identifier for the show
-		MarketNumber int            `xml:"marketNumber,attr"` // The number of the betting market in which this show belongs
-		NoOffers     xmlYesNo       `xml:"noOffers"`          // Whether or not no price is being offered.  If no price is being offered, this has the value "Yes", otherwise the attribute is absent
-		Price        xmlPrice       `xml:"Price"`             // The price of the show
+		Units string `xml:"units,attr"` // The units in which the value is specified
+		Value string `xml:"value,attr"` // The value in the specified units
 	}{}
+
 	if err := d.DecodeElement(&data, &start); err != nil {
 		return err
 	}
+	if _, err := strconv.Atoi(data.Units); err == nil {
+		data.Units, data.Value = data.Value, data.Units
+	}
+	value := 0
+	if data.Value != "" {
+		var err error
+		if value, err = strconv.Atoi(data.Value); err != nil {
+			return fmt.Errorf("invalid Allowance value %q: %v", data.Value, err)
+		}
+	}
+	*a = xmlAllowance{
+		Units: data.Units,
+		Value: value,
+	}
+	return nil
+}
+
+// xmlShowData is the decoded Show element. Shows are the most frequent
+// elements, decoded elements are pooled to reduce allocations, see
+// xmlShow.UnmarshalXML.
+type xmlShowData struct {
+	Timestamp    xmlTimeElement `xml:"timestamp,attr"`    // The time at which the show was recieved (format ISO 8601:1988 yyyymmddThhmmss+/-hhmm). Acts as a unique identifier for the show
+	MarketNumber int            `xml:"marketNumber,attr"` // The number of the betting market in which this show belongs
+	NoOffers     xmlYesNo       `xml:"noOffers"`          // Whether or not no price is being offered.  If no price is being offered, this has the value "Yes", otherwise the attribute is absent
+	Price        xmlPrice       `xml:"Price"`             // The price of the show
+}
+
+var showDataPool = sync.Pool{New: func() interface{} { return new(xmlShowData) }}
+
+// UnmarshalXML implements xml.Unmarshaler interface.
+func (s *xmlShow) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	data := showDataPool.Get().(*xmlShowData)
+	defer func() {
+		*data = xmlShowData{}
+		showDataPool.Put(data)
+	}()
+	if err := d.DecodeElement(data, &start); err != nil {
+		return err
+	}
 	*s = xmlShow{
 		Timestamp:    time.Time(data.Timestamp),
 		MarketNumber: data.MarketNumber,
@@ -918,6 +1072,88 @@ func (s MeetingStatus) isValid() bool {
 	}
 }
 
+func (s RaceStatus) isValid() bool {
+	switch s {
+	case RaceDormant,
+		RaceDelayed,
+		RaceParading,
+		RaceGoingDown,
+		RaceAtThePost,
+		RaceGoingBehind,
+		RaceGoingInStalls,
+		RaceUnderOrders,
+		RaceOff,
+		RaceFinished,
+		RaceFalseStart,
+		RacePhotograph,
+		RaceResult,
+		RaceWeighedIn,
+		RaceRaceVoid,
+		RaceAbandoned:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s StewardsStatus) isValid() bool {
+	switch s {
+	case StewardsNone,
+		StewardsInquiry,
+		StewardsObjection,
+		StewardsInquiryAndObjection,
+		StewardsAmendedResult,
+		StewardsResultStands:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s CasualtyReason) isValid() bool {
+	switch s {
+	case NoCasualty,
+		Fell,
+		PulledUp,
+		UnseatedRider,
+		BroughtDown,
+		Refused,
+		CarriedOut,
+		RanOut,
+		SlippedUp,
+		HitRails,
+		RefusedToRace,
+		DidNotFinish,
+		LeftAtStart:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseCasualtyReason converts feed casualty reason to CasualtyReason value.
+// Spaced variants (e.g. "Carried Out") are accepted, unrecognised reasons are
+// returned as CasualtyUnknown together with the raw value.
+func parseCasualtyReason(s string) (CasualtyReason, string) {
+	reason := CasualtyReason(strings.ReplaceAll(s, " ", ""))
+	if !reason.isValid() {
+		return CasualtyUnknown, s
+	}
+	return reason, ""
+}
+
+func (s HorseStatus) isValid() bool {
+	switch s {
+	case HorseRunner,
+		HorseNonRunner,
+		HorseWithdrawn,
+		HorseReserve:
+		return true
+	default:
+		return false
+	}
+}
+
 // UnmarshalXMLAttr implements xml.UnmarshalerAttr intrface.
 func (b *xmlYesNo) UnmarshalXMLAttr(attr xml.Attr) error {
 	switch attr.Value {
@@ -996,6 +1232,33 @@ func parseDuration(s string) (time.Duration, error) {
 		time.Millisecond*time.Duration(mils), err
 }
 
+// FormatDuration is the reverse of parseDuration, it converts duration to ISO
+// 8601:1988 hhmmss.sss formatted string, leading zero hours and minutes are
+// omitted. Duration is truncated to milliseconds, empty string is returned for
+// zero duration.
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	hour := d / time.Hour
+	mins := d % time.Hour / time.Minute
+	secs := d % time.Minute / time.Second
+	mils := d % time.Second / time.Millisecond
+	switch {
+	case hour > 0:
+		return fmt.Sprintf("%s%02d%02d%02d.%03d", sign, hour, mins, secs, mils)
+	case mins > 0:
+		return fmt.Sprintf("%s%02d%02d.%03d", sign, mins, secs, mils)
+	default:
+		return fmt.Sprintf("%s%02d.%03d", sign, secs, mils)
+	}
+}
+
 // UnmarshalXML implements xml.Unmarshaler interface.
 func (t *xmlDuration) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	var data struct {
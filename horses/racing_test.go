@@ -1,6 +1,7 @@
 package horses
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
@@ -31,6 +32,14 @@ func makeTime(t *testing.T, s string) time.Time {
 	return tm
 }
 
+func timePtr(tm time.Time) *time.Time {
+	return &tm
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
 func makeRat(t *testing.T, s string) big.Rat {
 	r, ok := new(big.Rat).SetString(s)
 	if !ok {
@@ -76,7 +85,7 @@ func TestBulkParseHorseRacing(t *testing.T) {
 			obj, err := ParseRacingFile(blob)
 			require.NoError(t, err, path)
 
-			assert.True(t, len(obj.Meetings) == 1, "always exactly one meeting perfile")
+			assert.True(t, len(obj.Meetings) >= 1, "always at least one meeting per file")
 			for _, m := range obj.Meetings {
 				assert.True(t, len(m.Races) <= 1, "always at least one race per meeting")
 				for _, r := range m.Races {
@@ -86,12 +95,12 @@ func TestBulkParseHorseRacing(t *testing.T) {
 					catch("parsed Race with status GoingDown", r.Status == RaceGoingDown)
 					catch("parsed Race with status AtThePost", r.Status == RaceAtThePost)
 					catch("parsed Race with status GoingBehind", r.Status == RaceGoingBehind)
-					//catch("parsed Race with status GoingInStalls", r.Status == RaceStatusGoingInStalls)
-					//catch("parsed Race with status UnderOrders", r.Status == RaceStatusUnderOrders)
+					//catch("parsed Race with status GoingInStalls", r.Status == RaceGoingInStalls)
+					//catch("parsed Race with status UnderOrders", r.Status == RaceUnderOrders)
 					catch("parsed Race with status Off", r.Status == RaceOff)
 					catch("parsed Race with status Finished", r.Status == RaceFinished)
 					catch("parsed Race with status FalseStart", r.Status == RaceFalseStart)
-					//catch("parsed Race with status Photograph", r.Status == RaceStatusPhotograph)
+					//catch("parsed Race with status Photograph", r.Status == RacePhotograph)
 					catch("parsed Race with status Result", r.Status == RaceResult)
 					catch("parsed Race with status WeighedIn", r.Status == RaceWeighedIn)
 					catch("parsed Race with status Void", r.Status == RaceRaceVoid)
@@ -120,21 +129,23 @@ func TestBulkParseHorseRacing(t *testing.T) {
 						catch("parsed Horse with status Runner", h.Status == HorseRunner)
 						catch("parsed Horse with status NonRunner", h.Status == HorseNonRunner)
 						catch("parsed Horse with status Withdrawn", h.Status == HorseWithdrawn)
-						//catch("parsed Horse with status Reserve", h.Status == HorseStatusReserve)
+						//catch("parsed Horse with status Reserve", h.Status == HorseReserve)
 						if h.Result != nil {
 							catch("parsedHorseResultDisqualified", h.Result.Disqualified)
 							catch("parsedHorseResultAmendedPos", h.Result.AmendedPos != 0)
 						}
-						/*
-							if h.Status == HorseStatusWithdrawn {
-								assert.True(t, !h.WithdrawnTime.IsZero(), "Withdrawn time is always present if horse status is withdrawn")
-								assert.True(t, h.WithdrawnBetMarket != 0, "Withdrawn bet market is always non zero if horse status is withdrawn")
-							}*/
+						// horses withdrawn before any betting market is
+						// formed have no withdrawal details
+						if h.Status == HorseWithdrawn && len(r.BetMarkets) > 0 {
+							assert.True(t, !h.WithdrawnTime.IsZero(), "Withdrawn time is always present if horse status is withdrawn: %s", path)
+							assert.True(t, h.WithdrawnBetMarket != 0, "Withdrawn bet market is always non zero if horse status is withdrawn: %s", path)
+						}
 						catch("parsed Horse WithdrawnTime", !h.WithdrawnTime.IsZero())
 						catch("parsed Horse WithdrawnMarketNumber", h.WithdrawnBetMarket != 0)
 					}
 					for _, m := range r.BetMarkets {
 						catch("parsedBetMarketSuspended", !m.Suspended.IsZero())
+						assert.Equal(t, !m.Suspended.IsZero(), len(m.Suspensions) == 1, "Suspensions hold the suspension of a single message: %s", path)
 					}
 				}
 			}
@@ -209,10 +220,10 @@ func TestParseHorseRacing(t *testing.T) {
 								Jockey: Jockey{
 									ID:   1150396,
 									Name: "Harry Burns",
-									// Allowance: UnitsValue{
-									// 	Units: "lbs",
-									// 	Value: 7,
-									// },
+									Allowance: UnitsValue{
+										Units: "lbs",
+										Value: 7,
+									},
 								},
 								Trainer: Trainer{
 									ID:   131079,
@@ -246,10 +257,10 @@ func TestParseHorseRacing(t *testing.T) {
 								Jockey: Jockey{
 									ID:   1156790,
 									Name: "Poppy Bridgwater",
-									// Allowance: UnitsValue{
-									// 	Units: "lbs",
-									// 	Value: 7,
-									// },
+									Allowance: UnitsValue{
+										Units: "lbs",
+										Value: 7,
+									},
 								},
 								Trainer: Trainer{
 									ID:   12102,
@@ -339,10 +350,10 @@ func TestParseHorseRacing(t *testing.T) {
 								Jockey: Jockey{
 									ID:   1140493,
 									Name: "Shelley Birkett",
-									// Allowance: UnitsValue{
-									// 	Units: "lbs",
-									// 	Value: 3,
-									// },
+									Allowance: UnitsValue{
+										Units: "lbs",
+										Value: 3,
+									},
 								},
 								Trainer: Trainer{
 									ID:   14707,
@@ -371,10 +382,10 @@ func TestParseHorseRacing(t *testing.T) {
 								Jockey: Jockey{
 									ID:   1149121,
 									Name: "Paddy Bradley",
-									// Allowance: UnitsValue{
-									// 	Units: "lbs",
-									// 	Value: 5,
-									// },
+									Allowance: UnitsValue{
+										Units: "lbs",
+										Value: 5,
+									},
 								},
 								Trainer: Trainer{
 									ID:   3897,
@@ -545,8 +556,8 @@ func TestParseHorseRacing(t *testing.T) {
 						Weather:    "Overcast & Showers",
 						GoingBrief: "Good to Soft",
 						GoingFull:  "Good to Soft",
-						OffTime:    makeTime(t, "2018-11-28T12:15:49+00:00"),
-						WinTime:    4*time.Minute + 3*time.Second + 100*time.Millisecond,
+						OffTime:    timePtr(makeTime(t, "2018-11-28T12:15:49+00:00")),
+						WinTime:    durationPtr(4*time.Minute + 3*time.Second + 100*time.Millisecond),
 						//StewardsInquiry
 						//StewardsObjection
 						BetMarkets: []BetMarket{
@@ -734,10 +745,10 @@ func TestParseHorseRacing(t *testing.T) {
 								Jockey: Jockey{
 									ID:   1164129,
 									Name: "Mr P Armson",
-									// Allowance: UnitsValue{
-									// 	Units: "lbs",
-									// 	Value: 7,
-									// },
+									Allowance: UnitsValue{
+										Units: "lbs",
+										Value: 7,
+									},
 								},
 								Trainer: Trainer{
 									ID:   9194,
@@ -779,10 +790,10 @@ func TestParseHorseRacing(t *testing.T) {
 								Jockey: Jockey{
 									ID:   1154755,
 									Name: "Mr Alex Chadwick",
-									// Allowance: UnitsValue{
-									// 	Units: "lbs",
-									// 	Value: 7,
-									// },
+									Allowance: UnitsValue{
+										Units: "lbs",
+										Value: 7,
+									},
 									Overweight: UnitsValue{
 										Units: "lbs",
 										Value: 2,
@@ -944,10 +955,10 @@ func TestParseHorseRacing(t *testing.T) {
 								Jockey: Jockey{
 									ID:   1150129,
 									Name: "Harrison Beswick",
-									// Allowance: UnitsValue{
-									// 	Units: "lbs",
-									// 	Value: 7,
-									// },
+									Allowance: UnitsValue{
+										Units: "lbs",
+										Value: 7,
+									},
 								},
 								Trainer: Trainer{
 									ID:   125032,
@@ -1345,5 +1356,316 @@ func TestParseDuration(t *testing.T) {
 		d, err := parseDuration(test.s)
 		assert.Equal(t, test.d, d, fmt.Sprintf("input: %s", test.s))
 		assert.Equal(t, test.err, err, fmt.Sprintf("input: %s", test.s))
+		if err != nil {
+			continue
+		}
+		d, err = parseDuration(FormatDuration(d))
+		require.NoError(t, err, fmt.Sprintf("input: %s", test.s))
+		assert.Equal(t, test.d, d, fmt.Sprintf("round trip: %s", test.s))
+	}
+}
+
+func TestParseJockeyAllowance(t *testing.T) {
+	tests := []struct {
+		xml    string
+		jockey Jockey
+	}{
+		{
+			// Claiming apprentice
+			xml: `<Jockey id="1150396" name="Harry Burns"><Allowance units="lbs" value="7"/></Jockey>`,
+			jockey: Jockey{
+				ID:        1150396,
+				Name:      "Harry Burns",
+				Allowance: UnitsValue{Units: "lbs", Value: 7},
+			},
+		},
+		{
+			// Units and value swapped as seen in Australian feed
+			xml: `<Jockey id="1" name="Bonnie Palise"><Allowance units="3" value="lbs"/></Jockey>`,
+			jockey: Jockey{
+				ID:        1,
+				Name:      "Bonnie Palise",
+				Allowance: UnitsValue{Units: "lbs", Value: 3},
+			},
+		},
+		{
+			xml: `<Jockey id="1" name="Jim Crowley"><Overweight units="lbs" value="2"/></Jockey>`,
+			jockey: Jockey{
+				ID:         1,
+				Name:       "Jim Crowley",
+				Overweight: UnitsValue{Units: "lbs", Value: 2},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		var j xmlJockey
+		require.NoError(t, xml.Unmarshal([]byte(test.xml), &j), test.xml)
+		assert.Equal(t, test.jockey, Jockey(j), test.xml)
+	}
+}
+
+func TestParseHorseStatus(t *testing.T) {
+	var h xmlHorse
+	require.NoError(t, xml.Unmarshal([]byte(`<Horse id="1" name="Bonnie Grey" status="Reserve"/>`), &h))
+	assert.Equal(t, HorseReserve, h.Status)
+
+	require.NoError(t, xml.Unmarshal([]byte(`<Horse id="1" name="Bonnie Grey" status="Unknown"/>`), &h))
+	assert.Equal(t, HorseStatus("Unknown"), h.Status)
+	assert.False(t, h.Status.isValid())
+}
+
+func TestParseReserveHorse(t *testing.T) {
+	obj := parseTestFile(t, "testdata/Synthetic/reserve-horse-race.xml")
+	assert.NoError(t, obj.Validate())
+	race := obj.Meetings[0].Races[0]
+	h, ok := race.HorseByID(1702231)
+	require.True(t, ok)
+	assert.Equal(t, HorseReserve, h.Status)
+}
+
+func TestParseHorseShowsOrder(t *testing.T) {
+	var h xmlHorse
+	require.NoError(t, xml.Unmarshal([]byte(`<Horse id="1" name="Bonnie Grey" status="Runner">
+	<Show timestamp="20180405T142033+0100" marketNumber="1"><Price numerator="7" denominator="2"/></Show>
+	<Show timestamp="20180405T141400+0100" marketNumber="1"><Price numerator="4" denominator="1"/></Show>
+	<Show timestamp="20180405T142417+0100" marketNumber="1"><Price numerator="9" denominator="2"/></Show>
+	<Show timestamp="20180405T141400+0100" marketNumber="2"><Price numerator="5" denominator="1"/></Show>
+</Horse>`), &h))
+	require.Len(t, h.Shows, 4)
+	assert.Equal(t, makeRat(t, "4/1"), h.Shows[0].Price)
+	assert.Equal(t, makeRat(t, "5/1"), h.Shows[1].Price)
+	assert.Equal(t, makeRat(t, "7/2"), h.Shows[2].Price)
+	assert.Equal(t, makeRat(t, "9/2"), h.Shows[3].Price)
+}
+
+func TestParseCasualtyReason(t *testing.T) {
+	tests := []struct {
+		text     string
+		reason   CasualtyReason
+		original string
+	}{
+		{text: "", reason: NoCasualty},
+		{text: "Fell", reason: Fell},
+		{text: "BroughtDown", reason: BroughtDown},
+		{text: "Carried Out", reason: CarriedOut},
+		{text: "Left At Start", reason: LeftAtStart},
+		{text: "Ran Out", reason: RanOut},
+		{text: "Baulked", reason: CasualtyUnknown, original: "Baulked"},
+	}
+
+	for _, test := range tests {
+		reason, original := parseCasualtyReason(test.text)
+		assert.Equal(t, test.reason, reason, test.text)
+		assert.Equal(t, test.original, original, test.text)
+	}
+}
+
+func TestParseCasualties(t *testing.T) {
+	race := testRace(t, "testdata/Aintree/b20180414ain17150059.xml")
+
+	reasons := map[CasualtyReason]int{}
+	for _, h := range race.Horses {
+		reasons[h.CasualtyReason]++
+		assert.Empty(t, h.CasualtyText, h.Name)
+	}
+	assert.NotZero(t, reasons[Fell])
+	assert.NotZero(t, reasons[BroughtDown])
+
+	horse, ok := race.HorseByID(1547854)
+	require.True(t, ok)
+	assert.Equal(t, BroughtDown, horse.CasualtyReason)
+}
+
+func TestParseRacingFileErrorContext(t *testing.T) {
+	blob, err := ioutil.ReadFile("testdata/WindsorRule4BoardPrices/b20180416wnd16200080.xml")
+	require.NoError(t, err)
+	// first runner in the file
+	blob = bytes.Replace(blob, []byte(`timestamp="20180416T161007+0100"`), []byte(`timestamp="yesterday"`), 1)
+
+	_, err = ParseRacingFile(blob)
+	assert.EqualError(t, err, `meeting 97227: race 798648: horse 1761741: parsing { timestamp} attribute (yesterday): parsing time "yesterday" as "20060102T1504-0700": cannot parse "yesterday" as "2006"`)
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d time.Duration
+		s string
+	}{
+		{
+			d: 0,
+			s: "",
+		},
+		{
+			d: time.Second*23 + time.Millisecond*900 + time.Microsecond*999,
+			s: "23.900",
+		},
+		{
+			d: time.Minute*4 + time.Second*3 + time.Millisecond*500,
+			s: "0403.500",
+		},
+		{
+			d: time.Hour + time.Minute*2,
+			s: "010200.000",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.s, FormatDuration(test.d), test.d.String())
+	}
+}
+
+func TestParseRaceOffTime(t *testing.T) {
+	dormant := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200010.xml")
+	assert.Nil(t, dormant.OffTime)
+	assert.Nil(t, dormant.WinTime)
+
+	// off time is known as soon as the race is off, win time comes later
+	off := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200072.xml")
+	require.NotNil(t, off.OffTime)
+	assert.False(t, off.OffTime.IsZero())
+	assert.Nil(t, off.WinTime)
+
+	result := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200085.xml")
+	require.NotNil(t, result.OffTime)
+	require.NotNil(t, result.WinTime)
+	assert.NotZero(t, *result.WinTime)
+}
+
+func TestParseHorseWithdrawn(t *testing.T) {
+	race := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200076.xml")
+	var withdrawn []Horse
+	for _, h := range race.Horses {
+		if h.Status == HorseWithdrawn {
+			withdrawn = append(withdrawn, h)
+		}
+	}
+	require.Len(t, withdrawn, 1)
+	assert.Equal(t, 1, withdrawn[0].WithdrawnBetMarket)
+	assert.True(t, makeTime(t, "2018-04-16T16:23:30+01:00").Equal(withdrawn[0].WithdrawnTime))
+}
+
+func TestParseMultipleMeetings(t *testing.T) {
+	obj := parseTestFile(t, "testdata/Synthetic/wetherby-and-newcastle-meetings.xml")
+	assert.NoError(t, obj.Validate())
+	require.Len(t, obj.Meetings, 2)
+	assert.Equal(t, 104930, obj.Meetings[0].ID)
+	assert.Equal(t, "Wetherby", obj.Meetings[0].Course)
+	assert.Equal(t, 97344, obj.Meetings[1].ID)
+	assert.Equal(t, "Newcastle", obj.Meetings[1].Course)
+	for _, m := range obj.Meetings {
+		require.Len(t, m.Races, 1)
+		for _, row := range m.RunnerRows() {
+			assert.Equal(t, m.ID, row.MeetingID)
+		}
+	}
+
+	summary := obj.Summary()
+	assert.Equal(t, 2, summary.Meetings)
+	assert.Equal(t, 2, summary.Races)
+
+	first, last := obj.TimeWindow()
+	assert.Equal(t, obj.Meetings[1].Races[0].StartTime, first)
+	assert.Equal(t, obj.Meetings[0].Races[0].StartTime, last)
+	assert.NoError(t, obj.Validate())
+}
+
+const benchmarkHorseXML = `<Horse id="1761741" name="Blaine" bred="GB" status="Runner">
+  <Cloth number="1"/>
+  <Weight units="lbs" value="133" text="9st 7lbs"/>
+  <Jockey id="13937" name="J P Spencer"/>
+  <Trainer id="108511" name="B Barr"/>
+  <Show timestamp="20180416T161007+0100" marketNumber="1"><Price numerator="25" denominator="1"/></Show>
+  <Show timestamp="20180416T161142+0100" marketNumber="1"><Price numerator="20" denominator="1"/></Show>
+  <Show timestamp="20180416T161929+0100" marketNumber="1"><Price numerator="25" denominator="1"/></Show>
+  <Show timestamp="20180416T162402+0100" marketNumber="2"><Price numerator="20" denominator="1"/></Show>
+  <Show timestamp="20180416T162438+0100" marketNumber="2"><Price numerator="25" denominator="1"/></Show>
+  <Show timestamp="20180416T162536+0100" marketNumber="2"><Price numerator="20" denominator="1"/></Show>
+  <StartingPrice>
+    <Price numerator="20" denominator="1"/>
+    <Favourite position="6" joint="3"/>
+  </StartingPrice>
+  <BetMovements comment="Mkt1: op 25/1; Mkt2 tchd 25/1"/>
+</Horse>`
+
+func BenchmarkUnmarshalHorse(b *testing.B) {
+	blob := []byte(benchmarkHorseXML)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var horse xmlHorse
+		if err := xml.Unmarshal(blob, &horse); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalShow(b *testing.B) {
+	blob := []byte(`<Show timestamp="20180416T161007+0100" marketNumber="1"><Price numerator="25" denominator="1"/></Show>`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var show xmlShow
+		if err := xml.Unmarshal(blob, &show); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkParse(b *testing.B, file string, parse func([]byte) error) {
+	blob, err := ioutil.ReadFile(file)
+	require.NoError(b, err)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(blob)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := parse(blob); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseRacingFile(b *testing.B) {
+	for _, file := range []string{
+		"testdata/Aintree/b20180414ain0001.xml",
+		"testdata/Lingfield/b20180414lin17400042.xml",
+		"testdata/feed/b20181128wth12150045.xml",
+	} {
+		b.Run(path.Base(file), func(b *testing.B) {
+			benchmarkParse(b, file, func(blob []byte) error {
+				_, err := ParseRacingFile(blob)
+				return err
+			})
+		})
+	}
+}
+
+func BenchmarkParseRacingCardFile(b *testing.B) {
+	for _, file := range []string{
+		"testdata/Aintree/c20180414ain_8.xml",
+		"testdata/Lingfield/c20180414lin.xml",
+	} {
+		b.Run(path.Base(file), func(b *testing.B) {
+			benchmarkParse(b, file, func(blob []byte) error {
+				_, err := ParseRacingCardFile(blob)
+				return err
+			})
+		})
+	}
+}
+
+func TestParseInRunningRaceStatus(t *testing.T) {
+	tests := []struct {
+		path   string
+		status RaceStatus
+	}{
+		{"testdata/Synthetic/going-in-stalls-race.xml", RaceGoingInStalls},
+		{"testdata/Synthetic/under-orders-race.xml", RaceUnderOrders},
+		{"testdata/Synthetic/photograph-race.xml", RacePhotograph},
+	}
+	for _, test := range tests {
+		obj := parseTestFile(t, test.path)
+		assert.NoError(t, obj.Validate(), test.path)
+		require.Len(t, obj.Meetings, 1, test.path)
+		require.Len(t, obj.Meetings[0].Races, 1, test.path)
+		assert.Equal(t, test.status, obj.Meetings[0].Races[0].Status, test.path)
 	}
 }
@@ -3,6 +3,7 @@ package horses
 import (
 	"encoding/xml"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/advbet/decimal"
@@ -54,12 +55,12 @@ type CardRace struct {
 	Prizes        map[int]decimal.Number // Map from finishing position to prize amount
 	Eligibility   string                 // The type of horses eligible for the race. Example: 3yo plus.
 	Distance      UnitsValueText         // The distance of the race
+	WeightsRaised *UnitsValue            // Amount weights raised (at overnight stage)
 	Horses        []CardHorse            // The horse(s)
 	//LastWinner      *TODO   // The winner of corresponding race last year
 	//Totes          []TODO   // Tote bets applicable to this race
 	//DeclarationStage UNUSED //Declaration stage of the race. Early - used for early declarations (fourday etc). Final - used for final declarations (overnight etc)
 	//Fees             UNUSED // Fees associated with the race
-	//WeightsRaised    UNUSED // Amount weights raised (at overnight stage)
 	//Conditions       UNUSED // The conditions for the race (penalty weights etc)
 	//Televised        UNUSED // Television coverage details
 	//RaceFlags        UNUSED // Optional extra info breaking down type of race etc.
@@ -77,12 +78,13 @@ type xmlCardRace CardRace
 // object is sent in race cards and include more details then general Horse
 // object that is in normal racing messages.
 type CardHorse struct {
-	ID          int             // The internal identifier for the horse
-	Name        string          // The name of the horse
-	Bred        string          // The country of breeding of the horse
-	Status      CardHorseStatus // Horse status - Runner, Doubtful
-	ClothNumber int             // The saddlecloth number for the horse
-	DrawnStall  int             // The stall the horse starts from (Flat races only)
+	ID           int             // The internal identifier for the horse
+	Name         string          // The name of the horse
+	Bred         string          // The country of breeding of the horse
+	Status       CardHorseStatus // Horse status - Runner, Doubtful
+	ClothNumber  int             // The saddlecloth number for the horse
+	ClothCoupled string          // Letter distinguishing coupled horses sharing the same number e.g. "a" for 1a, empty if not coupled
+	DrawnStall   int             // The stall the horse starts from (Flat races only)
 	//FormFigures     []struct{}      // Recent form (figures) for the horse
 	//LastRunDays     []struct{}      // Number of days since the horse last ran
 	//RaceHistoryStat []struct{}      // The race history for the horse
@@ -111,30 +113,54 @@ type CardHorse struct {
 	//LongHandicap    *struct{}       // The long handicap details for this horse (if applicable)
 	//Medication      *struct{}       // Medication taken by the horse in the form race
 	//Travelled       *struct{}       // Distance travelled by horse to course
-	//FormRace        []struct{}      // Previous race form for this horse
+	FormRaces []CardFormRace // Previous race form for this horse
 	//PinSticker      []struct{}      // Pin sticker comments
-	//Analysis        *struct{}       // Analysis of horses chance of winning
+	Analysis *Comment // Analysis of horses chance of winning
 	//Message         UNUSED       // Other textual messages associated with horse
 }
 
 type xmlCardHorse CardHorse
 
+// CardFormRace holds details of a single previous run of the horse.
+type CardFormRace struct {
+	ID             int            // The internal identifier for the race
+	Date           time.Time      // The date of the race (format ISO 8601:1988 yyyymmdd)
+	Course         string         // The course at which the race was held
+	Country        string         // The country in which the race was held
+	RaceType       RaceType       // Type of race (Flat, Hurdle, Chase, National Hunt Flat)
+	Distance       UnitsValueText // The distance of the race
+	Going          string         // Brief description of going e.g. "Good"
+	Runners        int            // Number of runners in the race
+	FinishPos      int            // Finishing position of the horse, zero if horse did not finish
+	BeatenDistance string         // Distance behind preceding finisher
+	StartingPrice  StartingPrice  // The starting price of the horse
+}
+
+type xmlCardFormRace CardFormRace
+
 // CardTrainer holds horse trainer details. This field is sent with racing cards
 // and have more information then Trainer object.
 type CardTrainer struct {
-	ID          int    // Identifier for trainer
-	Name        string // The name of the trainer
-	Nationality string // The nationality of the trainer eg IRE
-	Location    string // Where the trainer is based
-	//PersonForm UNUSED // Indicates how well the trainer is currently doing
+	ID          int         // Identifier for trainer
+	Name        string      // The name of the trainer
+	Nationality string      // The nationality of the trainer eg IRE
+	Location    string      // Where the trainer is based
+	Form        *PersonForm // Indicates how well the trainer is currently doing
+}
+
+type xmlCardTrainer CardTrainer
+
+// PersonForm indicates how well a trainer or a jockey is currently doing.
+type PersonForm struct {
+	Wins   int    // Number of winners in the period
+	Runs   int    // Number of runners in the period
+	Period string // The period the form is calculated for e.g. 14 days
 }
 
-type xmlCardTrainer struct {
-	ID          int    `xml:"id,attr"`          // Identifier for trainer
-	Name        string `xml:"name,attr"`        // The name of the trainer
-	Nationality string `xml:"nationality,attr"` // The nationality of the trainer eg IRE
-	Location    string `xml:"location,attr"`    // Where the trainer is based
-	//PersonForm UNUSED `xml:"PersonForm"` // Indicates how well the trainer is currently doing
+type xmlPersonForm struct {
+	Wins   int    `xml:"wins,attr"`   // Number of winners in the period
+	Runs   int    `xml:"runs,attr"`   // Number of runners in the period
+	Period string `xml:"period,attr"` // The period the form is calculated for e.g. 14 days
 }
 
 // CardHorseStatus is an enum for horse status values.
@@ -144,10 +170,10 @@ type CardHorseStatus string
 // object is sent only in race cards and contains less detauls than Jockey
 // object.
 type CardJockey struct {
-	ID        int        // Identifier for jockey
-	Name      string     // The name of the jockey
-	Allowance UnitsValue // Allowance of the jockey units in which allowance value is pecified
-	//PersonForm UNUSED  // Indicates how well the jockey is currently doing
+	ID        int         // Identifier for jockey
+	Name      string      // The name of the jockey
+	Allowance UnitsValue  // Allowance of the jockey units in which allowance value is pecified
+	Form      *PersonForm // Indicates how well the jockey is currently doing
 }
 
 type xmlCardJockey CardJockey
@@ -167,6 +193,17 @@ type xmlBreeding struct {
 	YearBorn int           `xml:"yearBorn,attr"` // When the horse was born (if known)
 }
 
+// Comment is a textual editorial comment together with its source.
+type Comment struct {
+	Source string // Source of the comment e.g. PA
+	Text   string // Comment text
+}
+
+type xmlComment struct {
+	Source string `xml:"source,attr"` // Source of the comment e.g. PA
+	Text   string `xml:",chardata"`   // Comment text
+}
+
 // HorseRelation describes a breeding relation between two horses.
 type HorseRelation string
 
@@ -218,8 +255,9 @@ const (
 
 // List of allowed CardHorseStatus values.
 const (
-	CardHorseRunner   CardHorseStatus = "Runner"
-	CardHorseDoubtful CardHorseStatus = "Doubtful"
+	CardHorseRunner    CardHorseStatus = "Runner"
+	CardHorseDoubtful  CardHorseStatus = "Doubtful"
+	CardHorseNonRunner CardHorseStatus = "NonRunner" // declared horse withdrawn from the race, e.g. in Australian cards
 )
 
 // List of allowed horse sex values.
@@ -282,7 +320,7 @@ func (m *xmlCardMeeting) UnmarshalXML(d *xml.Decoder, start xml.StartElement) er
 		//MultiBets         []struct{ TODO }  `xml:"MultiBet"`        // Multi-race bets available on this meeting
 	}{}
 	if err := d.DecodeElement(&data, &start); err != nil {
-		return err
+		return fmt.Errorf("meeting %d: %w", data.ID, err)
 	}
 	var races []CardRace
 	for _, r := range data.Races {
@@ -329,17 +367,17 @@ func (r *xmlCardRace) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 		PrizeMoney   struct {
 			Currency string `xml:"currency,attr"` // The currency of the prize money
 			Prize    []struct {
-				Position int `xml:"position,attr"` // Finishing position the prize is for
-				Amount   int `xml:"amount,attr"`   // Prize amount (currency specified in PrizeMoney element)
+				Position int    `xml:"position,attr"` // Finishing position the prize is for
+				Amount   string `xml:"amount,attr"`   // Prize amount (currency specified in PrizeMoney element)
 			} `xml:"Prize"` // Prize Element
-		} // Prize money awarded for the race
+		} `xml:"Prizes"` // Prize money awarded for the race
 		//Fees UNUSED `xml:"Fees"           // Fees associated with the race
 		Eligibility struct {
 			Type string `xml:"type,attr"` // The type of horses eligible for the race. Example: 3yo plus.
 		} `xml:"Eligibility"` // The horses eligible in the race
-		Distance xmlUnitsValueText `xml:"Distance"` // The distance of the race
-		//WeightsRaised UNUSED `xml:"WeightsRaised"`  // Amount weights raised (at overnight stage)
-		LastWinner *struct {
+		Distance      xmlUnitsValueText `xml:"Distance"`      // The distance of the race
+		WeightsRaised *xmlUnitsValue    `xml:"WeightsRaised"` // Amount weights raised (at overnight stage)
+		LastWinner    *struct {
 			Year         int    `xml:"year,attr"`   // The year of the corresponding race
 			NoRaceReason string `xml:"noRace,attr"` // Reason if race was not run
 			Runners      int    `xml:"ran,attr"`    // The number of horses that raced
@@ -357,15 +395,28 @@ func (r *xmlCardRace) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 		Horses []xmlCardHorse `xml:"Horse"` // The horse(s)
 	}{}
 	if err := d.DecodeElement(&data, &start); err != nil {
-		return err
+		return fmt.Errorf("race %d: %w", data.ID, err)
 	}
 	startTime, err := time.Parse("20060102T1504-0700", fmt.Sprintf("%sT%s", data.Date, data.Time))
 	if err != nil {
-		return fmt.Errorf("parsing CardRace.date and CardRace.time: %v", err)
+		return fmt.Errorf("race %d: parsing CardRace.date and CardRace.time: %w", data.ID, err)
 	}
 	prizes := make(map[int]decimal.Number)
 	for _, prize := range data.PrizeMoney.Prize {
-		prizes[prize.Position] = decimal.FromInt(prize.Amount)
+		amount, err := parsePrizeAmount(prize.Amount)
+		if err != nil {
+			return fmt.Errorf("race %d: parsing Prize.amount: %w", data.ID, err)
+		}
+		prizes[prize.Position] = amount
+	}
+	var addedMoney, penaltyValue *MoneyValue
+	if data.AddedMoney != nil {
+		m := MoneyValue(*data.AddedMoney).WithDefaultCurrency(data.PrizeMoney.Currency)
+		addedMoney = &m
+	}
+	if data.PenaltyValue != nil {
+		m := MoneyValue(*data.PenaltyValue).WithDefaultCurrency(data.PrizeMoney.Currency)
+		penaltyValue = &m
 	}
 	var horses []CardHorse
 	for _, h := range data.Horses {
@@ -384,14 +435,14 @@ func (r *xmlCardRace) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 		MaxRunners:    data.MaxRunners,
 		NumFences:     data.NumFences,
 		Title:         data.Title.Data,
-		AddedMoney:    (*MoneyValue)(data.AddedMoney),
-		PenaltyValue:  (*MoneyValue)(data.PenaltyValue),
+		AddedMoney:    addedMoney,
+		PenaltyValue:  penaltyValue,
 		PrizeCurrency: data.PrizeMoney.Currency,
 		Prizes:        prizes,
 		//Fees        UNUSED
-		Eligibility: data.Eligibility.Type,
-		Distance:    UnitsValueText(data.Distance),
-		//WeightsRaised   UNUSED
+		Eligibility:   data.Eligibility.Type,
+		Distance:      UnitsValueText(data.Distance),
+		WeightsRaised: (*UnitsValue)(data.WeightsRaised),
 		//LastWinner      *TODO
 		//Conditions      UNUSED
 		//Televised       UNUSED
@@ -407,6 +458,16 @@ func (r *xmlCardRace) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 	return nil
 }
 
+// parsePrizeAmount parses prize amount, decimal places and thousands
+// separators are allowed, e.g. "1,250.50". Empty amount is parsed as zero.
+func parsePrizeAmount(s string) (decimal.Number, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	if s == "" {
+		return decimal.Number{}, nil
+	}
+	return decimal.FromString(s)
+}
+
 // UnmarshalXML implements xml.Unmarshaler interface.
 func (h *xmlCardHorse) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	data := struct {
@@ -415,8 +476,8 @@ func (h *xmlCardHorse) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 		Bred   string          `xml:"bred,attr"`   // The country of breeding of the horse
 		Status CardHorseStatus `xml:"status,attr"` // Horse status - Runner, Doubtful
 		Cloth  struct {
-			Number int `xml:"number,attr"` // Saddlecloth or racecard number of horse
-			//Coupled UNUSED `xml:"coupled,attr"` // In races where two or more horses have been "coupled" together, these horses share the same "number" but have an additional letter to be able to tell them apart. For example 1 and 1a.
+			Number  int    `xml:"number,attr"`  // Saddlecloth or racecard number of horse
+			Coupled string `xml:"coupled,attr"` // In races where two or more horses have been "coupled" together, these horses share the same "number" but have an additional letter to be able to tell them apart. For example 1 and 1a.
 		} `xml:"Cloth"` // The saddlecloth number for the horse
 		Drawn struct {
 			Stall int `xml:"stall"` // The stall this horse will start from
@@ -461,15 +522,15 @@ func (h *xmlCardHorse) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 		//LongHandicap    *struct{}  `xml:"LongHandicap"`    // The long handicap details for this horse (if applicable)
 		//Medication      *struct{}  `xml:"Medication"`      // Medication taken by the horse in the form race
 		//Travelled       *struct{}  `xml:"Travelled"`       // Distance travelled by horse to course
-		//FormRace        []struct{} `xml:"FormRace"`        // Previous race form for this horse
+		FormRaces []xmlCardFormRace `xml:"FormRace"` // Previous race form for this horse
 		//PinSticker      []struct{} `xml:"PinSticker"`      // Pin sticker comments
-		//Analysis        *struct{}  `xml:"Analysis"`        // Analysis of horses chance of winning
+		Analysis *xmlComment `xml:"Analysis"` // Analysis of horses chance of winning
 		//Message       UNUSED  `xml:"Message"`         // Other textual messages associated with horse
 	}{
 		Status: CardHorseRunner,
 	}
 	if err := d.DecodeElement(&data, &start); err != nil {
-		return err
+		return fmt.Errorf("horse %d: %w", data.ID, err)
 	}
 	colours := make([]string, 0, len(data.Colours))
 	for _, c := range data.Colours {
@@ -483,12 +544,17 @@ func (h *xmlCardHorse) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 	for _, r := range data.Ratings {
 		ratings = append(ratings, Rating(r))
 	}
+	var formRaces []CardFormRace
+	for _, r := range data.FormRaces {
+		formRaces = append(formRaces, CardFormRace(r))
+	}
 	*h = xmlCardHorse{
 		ID:                data.ID,
 		Name:              data.Name,
 		Bred:              data.Bred,
 		Status:            data.Status,
 		ClothNumber:       data.Cloth.Number,
+		ClothCoupled:      data.Cloth.Coupled,
 		DrawnStall:        data.Drawn.Stall,
 		AgeInYears:        data.Age.Years,
 		Weight:            UnitsValueText(data.Weight),
@@ -503,6 +569,49 @@ func (h *xmlCardHorse) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 		Sex:               data.Sex.Type,
 		Breeding:          breeding,
 		Ratings:           ratings,
+		FormRaces:         formRaces,
+		Analysis:          (*Comment)(data.Analysis),
+	}
+	return nil
+}
+
+// UnmarshalXML implements xml.Unmarshaler interface.
+func (r *xmlCardFormRace) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	data := struct {
+		ID       int               `xml:"id,attr"`       // The internal identifier for the race
+		Date     xmlDate           `xml:"date,attr"`     // The date of the race (format ISO 8601:1988 yyyymmdd)
+		Course   string            `xml:"course,attr"`   // The course at which the race was held
+		Country  string            `xml:"country,attr"`  // The country in which the race was held
+		RaceType RaceType          `xml:"raceType,attr"` // Type of race (Flat, Hurdle, Chase, National Hunt Flat)
+		Runners  int               `xml:"runners,attr"`  // Number of runners in the race
+		Distance xmlUnitsValueText `xml:"Distance"`      // The distance of the race
+		Going    struct {
+			Brief string `xml:"brief,attr"` // Brief description of going e.g. "Good"
+		} `xml:"Going"` // The going for the race
+		Result        *xmlResult       `xml:"Result"`        // Result details if horse completed the course
+		StartingPrice xmlStartingPrice `xml:"StartingPrice"` // The starting price of the horse
+	}{}
+	if err := d.DecodeElement(&data, &start); err != nil {
+		return err
+	}
+	var finishPos int
+	var beatenDistance string
+	if data.Result != nil {
+		finishPos = data.Result.FinishPos
+		beatenDistance = data.Result.BetweenDistance
+	}
+	*r = xmlCardFormRace{
+		ID:             data.ID,
+		Date:           time.Time(data.Date),
+		Course:         data.Course,
+		Country:        data.Country,
+		RaceType:       data.RaceType,
+		Distance:       UnitsValueText(data.Distance),
+		Going:          data.Going.Brief,
+		Runners:        data.Runners,
+		FinishPos:      finishPos,
+		BeatenDistance: beatenDistance,
+		StartingPrice:  StartingPrice(data.StartingPrice),
 	}
 	return nil
 }
@@ -514,7 +623,7 @@ func (j *xmlCardJockey) UnmarshalXML(d *xml.Decoder, start xml.StartElement) err
 		Name string `xml:"name,attr"` // The name of the jockey
 		// Removed due to a bug found in Australian feed
 		// Allowance xmlUnitsValue `xml:"Allowance"` // The allowance of the jockey
-		//PersonForm UNUSED  `xml:"PersonForm"` // Indicates how well the jockey is currently doing
+		Form *xmlPersonForm `xml:"PersonForm"` // Indicates how well the jockey is currently doing
 	}{}
 	if err := d.DecodeElement(&data, &start); err != nil {
 		return err
@@ -523,7 +632,29 @@ func (j *xmlCardJockey) UnmarshalXML(d *xml.Decoder, start xml.StartElement) err
 		ID:   data.ID,
 		Name: data.Name,
 		// Allowance: UnitsValue(data.Allowance),
-		//PersonForm UNUSED
+		Form: (*PersonForm)(data.Form),
+	}
+	return nil
+}
+
+// UnmarshalXML implements xml.Unmarshaler interface.
+func (t *xmlCardTrainer) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	data := struct {
+		ID          int            `xml:"id,attr"`          // Identifier for trainer
+		Name        string         `xml:"name,attr"`        // The name of the trainer
+		Nationality string         `xml:"nationality,attr"` // The nationality of the trainer eg IRE
+		Location    string         `xml:"location,attr"`    // Where the trainer is based
+		Form        *xmlPersonForm `xml:"PersonForm"`       // Indicates how well the trainer is currently doing
+	}{}
+	if err := d.DecodeElement(&data, &start); err != nil {
+		return err
+	}
+	*t = xmlCardTrainer{
+		ID:          data.ID,
+		Name:        data.Name,
+		Nationality: data.Nationality,
+		Location:    data.Location,
+		Form:        (*PersonForm)(data.Form),
 	}
 	return nil
 }
@@ -1,11 +1,13 @@
 package horses
 
 import (
+	"encoding/xml"
 	"fmt"
 	"io/ioutil"
 	"path"
 	"testing"
 
+	"github.com/advbet/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -46,6 +48,7 @@ func TestBulkParseHorseRacingCard(t *testing.T) {
 			require.NoError(t, err, path)
 			cards, err := ParseRacingCardFile(blob)
 			require.NoError(t, err, path)
+			assert.NoError(t, cards.Validate(), path)
 
 			assert.True(t, len(*cards) == 1, "always exactly one meeting card per file")
 			for _, m := range *cards {
@@ -82,3 +85,308 @@ func TestBulkParseHorseRacingCard(t *testing.T) {
 		assert.True(t, value, check)
 	}
 }
+
+func TestParseCardHorseFormRaces(t *testing.T) {
+	blob := []byte(`
+<Horse id="2279062" name="Pride Of Angels" bred="GB">
+  <Cloth number="1"/>
+  <FormRace id="787001" date="20180215" course="Lingfield" country="England" raceType="Flat" runners="9">
+    <Distance units="yards" value="1541" text="0m 7f 1y"/>
+    <Going brief="Standard"/>
+    <Result finishPos="2" btnDistance="1 1/4 length"/>
+    <StartingPrice>
+      <Price numerator="11" denominator="4"/>
+      <Favourite position="1" joint="1"/>
+    </StartingPrice>
+  </FormRace>
+  <FormRace id="786002" date="20180120" course="Kempton" country="England" raceType="Flat" runners="12">
+    <Distance units="yards" value="1760" text="1m"/>
+    <Going brief="Standard To Slow"/>
+    <StartingPrice>
+      <Price numerator="8" denominator="1"/>
+    </StartingPrice>
+  </FormRace>
+</Horse>`)
+
+	var h xmlCardHorse
+	require.NoError(t, xml.Unmarshal(blob, &h))
+	assert.Equal(t, []CardFormRace{
+		{
+			ID:             787001,
+			Date:           makeTime(t, "2018-02-15T00:00:00Z"),
+			Course:         "Lingfield",
+			Country:        "England",
+			RaceType:       RaceFlat,
+			Distance:       UnitsValueText{Units: "yards", Value: 1541, Text: "0m 7f 1y"},
+			Going:          "Standard",
+			Runners:        9,
+			FinishPos:      2,
+			BeatenDistance: "1 1/4 length",
+			StartingPrice: StartingPrice{
+				Price:             makeRat(t, "11/4"),
+				FavouritePosition: 1,
+				FavouriteJoint:    1,
+			},
+		},
+		{
+			ID:       786002,
+			Date:     makeTime(t, "2018-01-20T00:00:00Z"),
+			Course:   "Kempton",
+			Country:  "England",
+			RaceType: RaceFlat,
+			Distance: UnitsValueText{Units: "yards", Value: 1760, Text: "1m"},
+			Going:    "Standard To Slow",
+			Runners:  12,
+			StartingPrice: StartingPrice{
+				Price: makeRat(t, "8/1"),
+			},
+		},
+	}, h.FormRaces)
+}
+
+func TestParseCardHorseAnalysis(t *testing.T) {
+	tests := []struct {
+		xml      string
+		analysis *Comment
+	}{
+		{
+			xml: `<Horse id="1"><Analysis source="PA">Consistent sort, should go well again.</Analysis></Horse>`,
+			analysis: &Comment{
+				Source: "PA",
+				Text:   "Consistent sort, should go well again.",
+			},
+		},
+		{
+			xml:      `<Horse id="1"></Horse>`,
+			analysis: nil,
+		},
+	}
+
+	for _, test := range tests {
+		var h xmlCardHorse
+		require.NoError(t, xml.Unmarshal([]byte(test.xml), &h))
+		assert.Equal(t, test.analysis, h.Analysis, test.xml)
+	}
+}
+
+func TestParseCardHorseCloth(t *testing.T) {
+	tests := []struct {
+		xml     string
+		number  int
+		coupled string
+	}{
+		{
+			xml:     `<Horse id="1"><Cloth number="1"/></Horse>`,
+			number:  1,
+			coupled: "",
+		},
+		{
+			xml:     `<Horse id="2"><Cloth number="1" coupled="a"/></Horse>`,
+			number:  1,
+			coupled: "a",
+		},
+	}
+
+	for _, test := range tests {
+		var h xmlCardHorse
+		require.NoError(t, xml.Unmarshal([]byte(test.xml), &h))
+		assert.Equal(t, test.number, h.ClothNumber, test.xml)
+		assert.Equal(t, test.coupled, h.ClothCoupled, test.xml)
+	}
+}
+
+func TestParseCardRaceWeightsRaised(t *testing.T) {
+	tests := []struct {
+		xml           string
+		weightsRaised *UnitsValue
+	}{
+		{
+			xml: `<Race id="1" date="20180414" time="1355+0100"><WeightsRaised units="pounds" value="3"/></Race>`,
+			weightsRaised: &UnitsValue{
+				Units: "pounds",
+				Value: 3,
+			},
+		},
+		{
+			xml:           `<Race id="1" date="20180414" time="1355+0100"></Race>`,
+			weightsRaised: nil,
+		},
+	}
+
+	for _, test := range tests {
+		var r xmlCardRace
+		require.NoError(t, xml.Unmarshal([]byte(test.xml), &r))
+		assert.Equal(t, test.weightsRaised, r.WeightsRaised, test.xml)
+	}
+}
+
+func TestParseCardPersonForm(t *testing.T) {
+	blob := []byte(`
+<Horse id="1">
+  <Trainer id="6170" name="G L Moore" nationality="GB" location="Horsham">
+    <PersonForm wins="3" runs="14" period="14 days"/>
+  </Trainer>
+  <Jockey id="1152160" name="Hector Crouch">
+    <PersonForm wins="1" runs="9" period="14 days"/>
+  </Jockey>
+</Horse>`)
+
+	var h xmlCardHorse
+	require.NoError(t, xml.Unmarshal(blob, &h))
+	assert.Equal(t, CardTrainer{
+		ID:          6170,
+		Name:        "G L Moore",
+		Nationality: "GB",
+		Location:    "Horsham",
+		Form: &PersonForm{
+			Wins:   3,
+			Runs:   14,
+			Period: "14 days",
+		},
+	}, h.Trainer)
+	assert.Equal(t, CardJockey{
+		ID:   1152160,
+		Name: "Hector Crouch",
+		Form: &PersonForm{
+			Wins:   1,
+			Runs:   9,
+			Period: "14 days",
+		},
+	}, h.Jockey)
+}
+
+func TestParseCardRaceMoneyCurrency(t *testing.T) {
+	tests := []struct {
+		xml          string
+		addedMoney   *MoneyValue
+		penaltyValue *MoneyValue
+	}{
+		{
+			xml: `<Race id="1" date="20180414" time="1355+0100">
+			  <AddedMoney amount="5000"/>
+			  <PenaltyValue currency="EUR" amount="3752"/>
+			  <Prizes currency="GBP"><Prize position="1" amount="3752"/></Prizes>
+			</Race>`,
+			addedMoney:   &MoneyValue{Currency: "GBP", Amount: decimal.FromInt(5000)},
+			penaltyValue: &MoneyValue{Currency: "EUR", Amount: decimal.FromInt(3752)},
+		},
+		{
+			xml: `<Race id="1" date="20180414" time="1355+0100">
+			  <Prizes currency="GBP"><Prize position="1" amount="3752"/></Prizes>
+			</Race>`,
+			addedMoney:   nil,
+			penaltyValue: nil,
+		},
+	}
+
+	for _, test := range tests {
+		var r xmlCardRace
+		require.NoError(t, xml.Unmarshal([]byte(test.xml), &r))
+		assert.Equal(t, test.addedMoney, r.AddedMoney, test.xml)
+		assert.Equal(t, test.penaltyValue, r.PenaltyValue, test.xml)
+		assert.Equal(t, "GBP", r.PrizeCurrency, test.xml)
+	}
+}
+
+func TestParseCardRacePrizes(t *testing.T) {
+	cards := parseTestCardFile(t, "testdata/Lingfield/c20180414lin.xml")
+	race := (*cards)[0].Races[0]
+	assert.Equal(t, "GBP", race.PrizeCurrency)
+	assert.Equal(t, decimal.FromInt(3752), race.Prizes[1])
+	assert.Equal(t, &MoneyValue{Currency: "GBP", Amount: decimal.FromInt(3752)}, race.PenaltyValue)
+}
+
+func TestParseCardRaceDecimalPrizes(t *testing.T) {
+	var r xmlCardRace
+	err := xml.Unmarshal([]byte(`<Race id="1" date="20180414" time="1355+0100">
+	  <Prizes currency="EUR">
+	    <Prize position="1" amount="8,850.00"/>
+	    <Prize position="2" amount="2,805.50"/>
+	    <Prize position="3" amount="708"/>
+	  </Prizes>
+	</Race>`), &r)
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", r.PrizeCurrency)
+	assert.Equal(t, map[int]decimal.Number{
+		1: decimal.New(885000, -2),
+		2: decimal.New(280550, -2),
+		3: decimal.FromInt(708),
+	}, r.Prizes)
+
+	err = xml.Unmarshal([]byte(`<Race id="1" date="20180414" time="1355+0100">
+	  <Prizes currency="EUR"><Prize position="1" amount="TBC"/></Prizes>
+	</Race>`), &r)
+	assert.Error(t, err)
+}
+
+func TestParsePrizeAmount(t *testing.T) {
+	tests := []struct {
+		amount   string
+		expected decimal.Number
+		ok       bool
+	}{
+		{amount: "3752", expected: decimal.FromInt(3752), ok: true},
+		{amount: "1,329.25", expected: decimal.New(132925, -2), ok: true},
+		{amount: "1,250,000", expected: decimal.FromInt(1250000), ok: true},
+		{amount: " 354.75 ", expected: decimal.New(35475, -2), ok: true},
+		{amount: "", expected: decimal.Number{}, ok: true},
+		{amount: "TBC", ok: false},
+	}
+	for _, test := range tests {
+		amount, err := parsePrizeAmount(test.amount)
+		if !test.ok {
+			assert.Error(t, err, test.amount)
+			continue
+		}
+		require.NoError(t, err, test.amount)
+		assert.Equal(t, test.expected, amount, test.amount)
+	}
+}
+
+func TestParseCardRaceTypes(t *testing.T) {
+	tests := []struct {
+		xml       string
+		raceType  RaceType
+		trackType TrackType
+		ok        bool
+	}{
+		{
+			xml:       `<Race id="1" date="20180414" time="1355+0100" raceType="N_H_Flat" trackType="Turf"/>`,
+			raceType:  RaceNationalHuntFlat,
+			trackType: TrackTurf,
+			ok:        true,
+		},
+		{
+			xml:       `<Race id="1" date="20180414" time="1355+0100" raceType="Flat" trackType="AllWeather"/>`,
+			raceType:  RaceFlat,
+			trackType: TrackAllWeather,
+			ok:        true,
+		},
+		{
+			xml:       `<Race id="1" date="20180414" time="1355+0100" raceType="Flat" trackType="Grass"/>`,
+			raceType:  RaceFlat,
+			trackType: TrackType("Grass"),
+			ok:        false,
+		},
+		{
+			xml:       `<Race id="1" date="20180414" time="1355+0100" raceType="Bumper" trackType="Turf"/>`,
+			raceType:  RaceType("Bumper"),
+			trackType: TrackTurf,
+			ok:        false,
+		},
+	}
+
+	for _, test := range tests {
+		// unknown values are kept as is and reported by Validate
+		var r xmlCardRace
+		require.NoError(t, xml.Unmarshal([]byte(test.xml), &r), test.xml)
+		assert.Equal(t, test.raceType, r.RaceType, test.xml)
+		assert.Equal(t, test.trackType, r.TrackType, test.xml)
+		card := RacingCardFile{{Races: []CardRace{CardRace(r)}}}
+		if test.ok {
+			assert.NoError(t, card.Validate(), test.xml)
+		} else {
+			assert.Error(t, card.Validate(), test.xml)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package horses
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Silks is the structured form of jockey colours description, e.g. "Royal
+// blue, yellow star, striped cap" is parsed to "royal blue" and "yellow star"
+// body tokens and "striped" cap token. Tokens are lower case and have the
+// "sleeves" and "cap" words removed.
+type Silks struct {
+	Raw     string   // Colours description as received
+	Body    []string // Body colour followed by body patterns
+	Sleeves []string // Sleeve colours and patterns
+	Cap     []string // Cap colours and patterns
+}
+
+// silksSection is a part of the silks the description token belongs to.
+type silksSection int
+
+const (
+	silksBody silksSection = iota
+	silksSleeves
+	silksCap
+)
+
+// silksOnRe matches the trailing part of a token naming a pattern on the
+// sleeves or the cap, e.g. "and star on cap", or a bare "and sleeves".
+var silksOnRe = regexp.MustCompile(`^(.+?) and ((?:[a-z ]+ on )?(?:sleeves|cap))$`)
+
+// ParseSilks parses jockey colours description. Comma separated parts are
+// assigned to the section they name, parts naming no section belong to the
+// body until sleeves or cap are named, then to the last named section.
+func ParseSilks(s string) Silks {
+	silks := Silks{Raw: s}
+	section := silksBody
+	var prev string
+	for i, part := range strings.Split(strings.ToLower(s), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		parts := []string{part}
+		if m := silksOnRe.FindStringSubmatch(part); m != nil && i > 0 {
+			parts = []string{m[1], m[2]}
+		}
+		for _, p := range parts {
+			token, named := silksToken(p)
+			if i > 0 && named != silksBody {
+				section = named
+			}
+			if token == "" {
+				// bare "sleeves" or "cap" share the colour of the previous part
+				token = silksColour(prev)
+			} else {
+				prev = p
+			}
+			if token == "" {
+				continue
+			}
+			switch section {
+			case silksSleeves:
+				silks.Sleeves = append(silks.Sleeves, token)
+			case silksCap:
+				silks.Cap = append(silks.Cap, token)
+			default:
+				silks.Body = append(silks.Body, token)
+			}
+		}
+	}
+	return silks
+}
+
+// silksToken strips the section naming words from a description part and
+// returns the section named, silksBody is returned if part names no section.
+func silksToken(part string) (string, silksSection) {
+	for _, suffix := range []struct {
+		word    string
+		section silksSection
+	}{
+		{"sleeves", silksSleeves},
+		{"sleeve", silksSleeves},
+		{"cap", silksCap},
+	} {
+		if part == suffix.word {
+			return "", suffix.section
+		}
+		if strings.HasSuffix(part, " "+suffix.word) {
+			token := strings.TrimSuffix(part, " "+suffix.word)
+			token = strings.TrimSuffix(token, " on")
+			return token, suffix.section
+		}
+	}
+	return part, silksBody
+}
+
+// silksColour returns the colour of a description part, that is all but the
+// last word, e.g. "light blue" of "light blue epaulets".
+func silksColour(part string) string {
+	if i := strings.LastIndex(part, " "); i >= 0 {
+		return part[:i]
+	}
+	return ""
+}
+
+// Silks returns the parsed jockey colours description of the horse.
+func (h *CardHorse) Silks() Silks {
+	return ParseSilks(h.JockeyColours)
+}
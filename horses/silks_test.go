@@ -0,0 +1,106 @@
+package horses
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSilks(t *testing.T) {
+	tests := []struct {
+		s     string
+		silks Silks
+	}{
+		{
+			s: "Royal blue, yellow star, striped cap",
+			silks: Silks{
+				Body: []string{"royal blue", "yellow star"},
+				Cap:  []string{"striped"},
+			},
+		},
+		{
+			s: "Grey and emerald green diabolo, emerald green sleeves, quartered cap",
+			silks: Silks{
+				Body:    []string{"grey and emerald green diabolo"},
+				Sleeves: []string{"emerald green"},
+				Cap:     []string{"quartered"},
+			},
+		},
+		{
+			s: "Pink, black chevron, pink sleeves, black spots, pink cap, black diamond",
+			silks: Silks{
+				Body:    []string{"pink", "black chevron"},
+				Sleeves: []string{"pink", "black spots"},
+				Cap:     []string{"pink", "black diamond"},
+			},
+		},
+		{
+			s: "Red, white stripe, red sleeves, white spots and spots on cap",
+			silks: Silks{
+				Body:    []string{"red", "white stripe"},
+				Sleeves: []string{"red", "white spots"},
+				Cap:     []string{"spots"},
+			},
+		},
+		{
+			s: "Royal blue, maroon sash and star on cap",
+			silks: Silks{
+				Body: []string{"royal blue", "maroon sash"},
+				Cap:  []string{"star"},
+			},
+		},
+		{
+			s: "Royal blue, white cross of lorraine, hooped sleeves and diamonds on cap",
+			silks: Silks{
+				Body:    []string{"royal blue", "white cross of lorraine"},
+				Sleeves: []string{"hooped"},
+				Cap:     []string{"diamonds"},
+			},
+		},
+		{
+			s: "Dark blue, light blue epaulets, sleeves and cap",
+			silks: Silks{
+				Body:    []string{"dark blue", "light blue epaulets"},
+				Sleeves: []string{"light blue"},
+				Cap:     []string{"light blue"},
+			},
+		},
+		{
+			s: "Emerald green, white spots, yellow sleeves, emerald green and white striped cap",
+			silks: Silks{
+				Body:    []string{"emerald green", "white spots"},
+				Sleeves: []string{"yellow"},
+				Cap:     []string{"emerald green and white striped"},
+			},
+		},
+		{
+			s: "Silver, tassel on cap",
+			silks: Silks{
+				Body: []string{"silver"},
+				Cap:  []string{"tassel"},
+			},
+		},
+		{
+			s:     "",
+			silks: Silks{},
+		},
+	}
+
+	for _, test := range tests {
+		test.silks.Raw = test.s
+		assert.Equal(t, test.silks, ParseSilks(test.s), test.s)
+	}
+}
+
+func TestCardHorseSilks(t *testing.T) {
+	cards := parseTestCardFile(t, "testdata/WindsorRule4BoardPrices/c20180416wnd.xml")
+	for _, race := range (*cards)[0].Races {
+		for _, h := range race.Horses {
+			silks := h.Silks()
+			assert.Equal(t, h.JockeyColours, silks.Raw)
+			if h.JockeyColours != "" {
+				assert.NotEmpty(t, silks.Body, h.JockeyColours)
+			}
+		}
+	}
+}
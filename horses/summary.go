@@ -0,0 +1,48 @@
+package horses
+
+// DocSummary holds document level statistics of a single RacingFile.
+type DocSummary struct {
+	Meetings      int  // Number of meetings in the document
+	Races         int  // Number of races across all meetings
+	Runners       int  // Number of horses having Runner status across all races
+	ResolvedRaces int  // Number of races which have a final outcome (result, void or abandoned)
+	StewardsCase  bool // Whether any race in the document has stewards involvement
+}
+
+// Summary returns meeting, race and runner counts for the document.
+func (r *RacingFile) Summary() DocSummary {
+	s := DocSummary{
+		Meetings: len(r.Meetings),
+	}
+	for _, m := range r.Meetings {
+		s.Races += len(m.Races)
+		for _, race := range m.Races {
+			if race.IsResolved() {
+				s.ResolvedRaces++
+			}
+			if race.Stewards != "" && race.Stewards != StewardsNone {
+				s.StewardsCase = true
+			}
+			for _, h := range race.Horses {
+				if h.Status == HorseRunner {
+					s.Runners++
+				}
+			}
+		}
+	}
+	return s
+}
+
+// IsResolved returns true if race has reached a final outcome: the result is
+// announced, the race was declared void or abandoned.
+func (r *Race) IsResolved() bool {
+	switch r.Status {
+	case RaceResult,
+		RaceWeighedIn,
+		RaceRaceVoid,
+		RaceAbandoned:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,40 @@
+package horses
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRacingFileSummary(t *testing.T) {
+	tests := []struct {
+		file    string
+		summary DocSummary
+	}{
+		{
+			file: "testdata/feed/b20181128wth12150045.xml",
+			summary: DocSummary{
+				Meetings:      1,
+				Races:         1,
+				Runners:       10,
+				ResolvedRaces: 1,
+				StewardsCase:  false,
+			},
+		},
+		{
+			file: "testdata/Lingfield/b20180414lin17400007.xml",
+			summary: DocSummary{
+				Meetings:      1,
+				Races:         1,
+				Runners:       10,
+				ResolvedRaces: 0,
+				StewardsCase:  false,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		obj := parseTestFile(t, test.file)
+		assert.Equal(t, test.summary, obj.Summary(), test.file)
+	}
+}
@@ -0,0 +1,53 @@
+package horses
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	var tracker Tracker
+	for _, file := range []string{
+		"testdata/WindsorRule4BoardPrices/b20180416wnd16200010.xml",
+		"testdata/WindsorRule4BoardPrices/b20180416wnd16200080.xml",
+	} {
+		tracker.ApplyFile(parseTestFile(t, file))
+	}
+	rf := parseTestFile(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200080.xml")
+	meetingID := rf.Meetings[0].ID
+	latest := rf.Meetings[0].Races[0]
+
+	// lower revision received out of order is ignored
+	stale := testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200011.xml")
+	assert.False(t, tracker.Apply(meetingID, stale))
+
+	race, ok := tracker.Race(meetingID, latest.ID)
+	require.True(t, ok)
+	assert.Equal(t, latest.Revision, race.Revision)
+	assert.Equal(t, latest.Status, race.Status)
+	assert.NotNil(t, race.Returns)
+
+	_, ok = tracker.Race(meetingID, -1)
+	assert.False(t, ok)
+	_, ok = tracker.Race(-1, latest.ID)
+	assert.False(t, ok)
+}
+
+func TestTrackerRaceUnchangedByApply(t *testing.T) {
+	var tracker Tracker
+	rf := parseTestFile(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200010.xml")
+	meetingID := rf.Meetings[0].ID
+	tracker.ApplyFile(rf)
+	race, ok := tracker.Race(meetingID, rf.Meetings[0].Races[0].ID)
+	require.True(t, ok)
+
+	for _, file := range []string{
+		"testdata/WindsorRule4BoardPrices/b20180416wnd16200011.xml",
+		"testdata/WindsorRule4BoardPrices/b20180416wnd16200080.xml",
+	} {
+		require.True(t, tracker.Apply(meetingID, testRace(t, file)))
+	}
+	assert.Equal(t, testRace(t, "testdata/WindsorRule4BoardPrices/b20180416wnd16200010.xml"), race)
+}
@@ -0,0 +1,142 @@
+package horses
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// poundsPerStone is the number of pounds in a stone.
+const poundsPerStone = 14
+
+// kilogramsPerPound is the number of kilograms in a pound.
+const kilogramsPerPound = 0.45359237
+
+// Distance conversion constants.
+const (
+	yardsPerMile    = 1760
+	yardsPerFurlong = 220
+	metresPerYard   = 0.9144
+)
+
+// distanceRe matches distance text written as miles, furlongs and yards, e.g.
+// "1m 2f 0y", "1m 2f" or "5f".
+var distanceRe = regexp.MustCompile(`^\s*(?:(\d+)m)?\s*(?:(\d+)f)?\s*(?:(\d+)y)?\s*$`)
+
+// stonePoundsRe matches weight text written as stones and pounds, e.g.
+// "9st 2lbs" or "9 2".
+var stonePoundsRe = regexp.MustCompile(`^\s*(\d+)\s*(?:st)?\s+(\d+)\s*(?:lbs?)?\s*$`)
+
+// isPounds returns true if units are pounds.
+func (v UnitsValueText) isPounds() bool {
+	return v.Units == "lbs" || v.Units == "pounds"
+}
+
+// Pounds returns weight in pounds parsed from the stones and pounds text, e.g.
+// "10st 12lbs" is 152 pounds. If units are pounds parsed weight must match the
+// value, value is returned if text is empty.
+func (v UnitsValueText) Pounds() (int, error) {
+	if v.Text == "" {
+		if v.isPounds() {
+			return v.Value, nil
+		}
+		return 0, fmt.Errorf("weight in %q units has no text", v.Units)
+	}
+	m := stonePoundsRe.FindStringSubmatch(v.Text)
+	if m == nil {
+		return 0, fmt.Errorf("parsing weight %q: expected stones and pounds", v.Text)
+	}
+	stones, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, fmt.Errorf("parsing weight %q: %s", v.Text, err)
+	}
+	pounds, err := strconv.Atoi(m[2])
+	if err != nil {
+		return 0, fmt.Errorf("parsing weight %q: %s", v.Text, err)
+	}
+	if pounds >= poundsPerStone {
+		return 0, fmt.Errorf("parsing weight %q: too many pounds", v.Text)
+	}
+	total := stones*poundsPerStone + pounds
+	if v.isPounds() && v.Value != 0 && v.Value != total {
+		return 0, fmt.Errorf("weight text %q does not match value %d %s", v.Text, v.Value, v.Units)
+	}
+	return total, nil
+}
+
+// Kilograms returns weight in kilograms, see Pounds for details.
+func (v UnitsValueText) Kilograms() (float64, error) {
+	pounds, err := v.Pounds()
+	if err != nil {
+		return 0, err
+	}
+	return float64(pounds) * kilogramsPerPound, nil
+}
+
+// isMetres returns true if units are metres.
+func (v UnitsValueText) isMetres() bool {
+	switch v.Units {
+	case "metres", "meters", "m":
+		return true
+	default:
+		return false
+	}
+}
+
+// yards returns distance in yards. Metric distances are taken from the value,
+// imperial ones are parsed from the miles, furlongs and yards text. If units
+// are yards parsed distance must match the value, value is returned if text is
+// empty.
+func (v UnitsValueText) yards() (float64, error) {
+	if v.isMetres() {
+		return float64(v.Value) / metresPerYard, nil
+	}
+	if v.Text == "" {
+		if v.Units == "yards" {
+			return float64(v.Value), nil
+		}
+		return 0, fmt.Errorf("distance in %q units has no text", v.Units)
+	}
+	m := distanceRe.FindStringSubmatch(v.Text)
+	if m == nil || (m[1] == "" && m[2] == "" && m[3] == "") {
+		return 0, fmt.Errorf("parsing distance %q: expected miles, furlongs and yards", v.Text)
+	}
+	yards := 0
+	for i, mult := range []int{yardsPerMile, yardsPerFurlong, 1} {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil {
+			return 0, fmt.Errorf("parsing distance %q: %s", v.Text, err)
+		}
+		yards += n * mult
+	}
+	if v.Units == "yards" && v.Value != 0 && v.Value != yards {
+		return 0, fmt.Errorf("distance text %q does not match value %d %s", v.Text, v.Value, v.Units)
+	}
+	return float64(yards), nil
+}
+
+// Furlongs returns distance in furlongs, e.g. "1m 2f" is 10 furlongs. Both
+// imperial (miles, furlongs and yards text) and metric (value in metres)
+// distances are supported.
+func (v UnitsValueText) Furlongs() (float64, error) {
+	yards, err := v.yards()
+	if err != nil {
+		return 0, err
+	}
+	return yards / yardsPerFurlong, nil
+}
+
+// Metres returns distance in metres, see Furlongs for details.
+func (v UnitsValueText) Metres() (float64, error) {
+	if v.isMetres() {
+		return float64(v.Value), nil
+	}
+	yards, err := v.yards()
+	if err != nil {
+		return 0, err
+	}
+	return yards * metresPerYard, nil
+}
@@ -0,0 +1,159 @@
+package horses
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitsValueTextPounds(t *testing.T) {
+	tests := []struct {
+		weight UnitsValueText
+		pounds int
+		err    bool
+	}{
+		{
+			weight: UnitsValueText{Units: "lbs", Value: 128, Text: "9st 2lbs"},
+			pounds: 128,
+		},
+		{
+			weight: UnitsValueText{Units: "lbs", Value: 152, Text: "10st 12lbs"},
+			pounds: 152,
+		},
+		{
+			weight: UnitsValueText{Units: "lbs", Value: 126, Text: "9st 0lbs"},
+			pounds: 126,
+		},
+		{
+			weight: UnitsValueText{Units: "pounds", Value: 135, Text: "9 9"},
+			pounds: 135,
+		},
+		{
+			weight: UnitsValueText{Units: "lbs", Value: 135},
+			pounds: 135,
+		},
+		{
+			weight: UnitsValueText{Text: "11st 7lbs"},
+			pounds: 161,
+		},
+		{
+			weight: UnitsValueText{Units: "lbs", Value: 130, Text: "9st 2lbs"},
+			err:    true,
+		},
+		{
+			weight: UnitsValueText{Units: "lbs", Value: 128, Text: "9st 14lbs"},
+			err:    true,
+		},
+		{
+			weight: UnitsValueText{Units: "lbs", Value: 128, Text: "heavy"},
+			err:    true,
+		},
+		{
+			weight: UnitsValueText{Units: "kg", Value: 58},
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		pounds, err := test.weight.Pounds()
+		if test.err {
+			assert.Error(t, err, test.weight.Text)
+			continue
+		}
+		assert.NoError(t, err, test.weight.Text)
+		assert.Equal(t, test.pounds, pounds, test.weight.Text)
+	}
+}
+
+func TestUnitsValueTextKilograms(t *testing.T) {
+	kg, err := UnitsValueText{Units: "lbs", Value: 140, Text: "10st 0lbs"}.Kilograms()
+	assert.NoError(t, err)
+	assert.InDelta(t, 63.503, kg, 0.001)
+
+	_, err = UnitsValueText{Units: "lbs", Value: 140, Text: "ten stone"}.Kilograms()
+	assert.Error(t, err)
+}
+
+func TestUnitsValueTextPoundsFeed(t *testing.T) {
+	for _, file := range []string{
+		"testdata/feed/b20181128wth12150045.xml",
+		"testdata/VaalLateWithdrawal/b20180405vaa14250035.xml",
+	} {
+		obj := parseTestFile(t, file)
+		for _, m := range obj.Meetings {
+			for _, r := range m.Races {
+				for _, h := range r.Horses {
+					pounds, err := h.Weight.Pounds()
+					assert.NoError(t, err, h.Weight.Text)
+					assert.Equal(t, h.Weight.Value, pounds, h.Weight.Text)
+				}
+			}
+		}
+	}
+}
+
+func TestUnitsValueTextFurlongs(t *testing.T) {
+	tests := []struct {
+		distance UnitsValueText
+		furlongs float64
+		metres   float64
+		err      bool
+	}{
+		{
+			distance: UnitsValueText{Units: "yards", Value: 2200, Text: "1m 2f 0y"},
+			furlongs: 10,
+			metres:   2011.68,
+		},
+		{
+			distance: UnitsValueText{Units: "yards", Value: 1121, Text: "0m 5f 21y"},
+			furlongs: 5.0955,
+			metres:   1025.0424,
+		},
+		{
+			distance: UnitsValueText{Text: "1m 2f"},
+			furlongs: 10,
+			metres:   2011.68,
+		},
+		{
+			distance: UnitsValueText{Text: "5f"},
+			furlongs: 5,
+			metres:   1005.84,
+		},
+		{
+			distance: UnitsValueText{Units: "yards", Value: 1760},
+			furlongs: 8,
+			metres:   1609.344,
+		},
+		{
+			distance: UnitsValueText{Units: "metres", Value: 1200},
+			furlongs: 5.965,
+			metres:   1200,
+		},
+		{
+			distance: UnitsValueText{Units: "yards", Value: 1000, Text: "1m 2f 0y"},
+			err:      true,
+		},
+		{
+			distance: UnitsValueText{Units: "yards", Value: 2200, Text: "long"},
+			err:      true,
+		},
+		{
+			distance: UnitsValueText{Units: "yards", Text: " "},
+			err:      true,
+		},
+	}
+
+	for _, test := range tests {
+		furlongs, err := test.distance.Furlongs()
+		metres, merr := test.distance.Metres()
+		if test.err {
+			assert.Error(t, err, test.distance.Text)
+			assert.Error(t, merr, test.distance.Text)
+			continue
+		}
+		assert.NoError(t, err, test.distance.Text)
+		assert.NoError(t, merr, test.distance.Text)
+		assert.InDelta(t, test.furlongs, furlongs, 0.001, test.distance.Text)
+		assert.InDelta(t, test.metres, metres, 0.001, test.distance.Text)
+	}
+}
@@ -1,12 +1,20 @@
 package horses
 
 import (
+	"bytes"
 	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/advbet/pafeed/internal/xmldoc"
 )
 
 // IsRacingFile given a file name returns true if file should should contain
-// Racing message.
+// Racing message. Unlike greyhound feed file names, Racing file names carry no
+// final results marker, use Race.IsFinalResult to tell final results apart.
 func IsRacingFile(name string) bool {
 	return strings.HasPrefix(name, "b")
 }
@@ -17,11 +25,144 @@ func IsRacingCardFile(name string) bool {
 	return strings.HasPrefix(name, "c")
 }
 
+// RacingFileInfo holds metadata encoded in a Racing file name.
+type RacingFileInfo struct {
+	Date     time.Time // Date of the meeting
+	Course   string    // Course abbreviation, e.g. "wth" for Wetherby
+	RaceTime string    // Scheduled race time in hhmm format, empty for meeting messages
+	Sequence int       // Message sequence number
+}
+
+// FilenameError is returned if file name does not match Racing file name
+// pattern.
+type FilenameError struct {
+	Name   string // The file name
+	Reason string // Description of mismatch
+}
+
+// Error implements error interface.
+func (e *FilenameError) Error() string {
+	return fmt.Sprintf("invalid racing file name %q: %s", e.Name, e.Reason)
+}
+
+// ParseRacingFilename parses Racing file name to structured metadata. Race
+// messages have b<date><course><racetime><sequence>.xml file names, e.g.
+// b20181128wth12150045.xml. Meeting messages have no race time, e.g.
+// b20180414ain0002.xml. FilenameError is returned if name does not match the
+// pattern.
+func ParseRacingFilename(name string) (RacingFileInfo, error) {
+	const (
+		dateLen   = 8
+		courseLen = 3
+		timeLen   = 4
+		seqLen    = 4
+	)
+	var info RacingFileInfo
+	if !IsRacingFile(name) || !strings.HasSuffix(name, ".xml") {
+		return info, &FilenameError{Name: name, Reason: "expected b<date><course>[<time>]<sequence>.xml"}
+	}
+	base := strings.TrimSuffix(strings.TrimPrefix(name, "b"), ".xml")
+	if len(base) != dateLen+courseLen+seqLen && len(base) != dateLen+courseLen+timeLen+seqLen {
+		return info, &FilenameError{Name: name, Reason: fmt.Sprintf("unexpected length %d", len(base))}
+	}
+	date, err := time.Parse("20060102", base[:dateLen])
+	if err != nil {
+		return info, &FilenameError{Name: name, Reason: "invalid date"}
+	}
+	course := base[dateLen : dateLen+courseLen]
+	for _, c := range course {
+		if c < 'a' || c > 'z' {
+			return info, &FilenameError{Name: name, Reason: "invalid course abbreviation"}
+		}
+	}
+	rest := base[dateLen+courseLen:]
+	var raceTime string
+	if len(rest) == timeLen+seqLen {
+		raceTime, rest = rest[:timeLen], rest[timeLen:]
+		if _, err := time.Parse("1504", raceTime); err != nil {
+			return info, &FilenameError{Name: name, Reason: "invalid race time"}
+		}
+	}
+	seq, err := strconv.Atoi(rest)
+	if err != nil || seq < 0 {
+		return info, &FilenameError{Name: name, Reason: "invalid sequence number"}
+	}
+	return RacingFileInfo{
+		Date:     date,
+		Course:   course,
+		RaceTime: raceTime,
+		Sequence: seq,
+	}, nil
+}
+
 // ParseRacingFile unmarshals Racing XML file contents to RacingFile object.
 // This function should be used for files that passes IsRacingFile() check.
 func ParseRacingFile(xmlBlob []byte) (*RacingFile, error) {
+	return parseRacingReader(bytes.NewReader(xmlBlob), xmldoc.NewDecoder)
+}
+
+// ParseRacingFileStrictUTF8 unmarshals Racing XML file contents to RacingFile
+// object like ParseRacingFile, except that documents not encoded in UTF-8 are
+// rejected instead of being decoded from their declared charset.
+func ParseRacingFileStrictUTF8(xmlBlob []byte) (*RacingFile, error) {
+	return parseRacingReader(bytes.NewReader(xmlBlob), xmldoc.NewStrictDecoder)
+}
+
+// ParseAllRacingFiles unmarshals every Racing XML document of file contents
+// holding several documents back to back. Whitespace between documents is
+// skipped, every document is decoded in the charset of its own XML
+// declaration. Error is returned if any of the documents is malformed.
+func ParseAllRacingFiles(xmlBlob []byte) ([]*RacingFile, error) {
+	return parseAllRacingFiles(xmlBlob, xmldoc.NewDecoder)
+}
+
+// ParseAllRacingFilesStrictUTF8 is like ParseAllRacingFiles, except that
+// documents not encoded in UTF-8 are rejected.
+func ParseAllRacingFilesStrictUTF8(xmlBlob []byte) ([]*RacingFile, error) {
+	return parseAllRacingFiles(xmlBlob, xmldoc.NewStrictDecoder)
+}
+
+func parseAllRacingFiles(xmlBlob []byte, newDecoder func(io.Reader) *xml.Decoder) ([]*RacingFile, error) {
+	var objs []*RacingFile
+	err := xmldoc.DecodeAll(xmlBlob, newDecoder, func(d *xml.Decoder) error {
+		var obj RacingFile
+		if err := d.Decode(&obj); err != nil {
+			return err
+		}
+		objs = append(objs, &obj)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+
+// ParseRacingReader unmarshals a single Racing XML document read from r to
+// RacingFile object. Unlike ParseRacingFile the document is streamed without
+// buffering whole file contents in memory. Gzip compressed documents are
+// decompressed transparently.
+func ParseRacingReader(r io.Reader) (*RacingFile, error) {
+	r, err := xmldoc.Decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseRacingReader(r, xmldoc.NewDecoder)
+}
+
+// ParseRacingReaderStrictUTF8 is like ParseRacingReader, except that documents
+// not encoded in UTF-8 are rejected.
+func ParseRacingReaderStrictUTF8(r io.Reader) (*RacingFile, error) {
+	r, err := xmldoc.Decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseRacingReader(r, xmldoc.NewStrictDecoder)
+}
+
+func parseRacingReader(r io.Reader, newDecoder func(io.Reader) *xml.Decoder) (*RacingFile, error) {
 	var obj RacingFile
-	if err := xml.Unmarshal(xmlBlob, &obj); err != nil {
+	if err := newDecoder(r).Decode(&obj); err != nil {
 		return nil, err
 	}
 	return &obj, nil
@@ -31,9 +172,117 @@ func ParseRacingFile(xmlBlob []byte) (*RacingFile, error) {
 // object. This function should be used for files that passes IsRacingCardFile()
 // check.
 func ParseRacingCardFile(xmlBlob []byte) (*RacingCardFile, error) {
+	return parseRacingCardReader(bytes.NewReader(xmlBlob), xmldoc.NewDecoder)
+}
+
+// ParseRacingCardFileStrictUTF8 is like ParseRacingCardFile, except that
+// documents not encoded in UTF-8 are rejected.
+func ParseRacingCardFileStrictUTF8(xmlBlob []byte) (*RacingCardFile, error) {
+	return parseRacingCardReader(bytes.NewReader(xmlBlob), xmldoc.NewStrictDecoder)
+}
+
+// ParseAllRacingCardFiles unmarshals every RacingCard XML document of file
+// contents holding several documents back to back, see ParseAllRacingFiles.
+func ParseAllRacingCardFiles(xmlBlob []byte) ([]*RacingCardFile, error) {
+	return parseAllRacingCardFiles(xmlBlob, xmldoc.NewDecoder)
+}
+
+// ParseAllRacingCardFilesStrictUTF8 is like ParseAllRacingCardFiles, except
+// that documents not encoded in UTF-8 are rejected.
+func ParseAllRacingCardFilesStrictUTF8(xmlBlob []byte) ([]*RacingCardFile, error) {
+	return parseAllRacingCardFiles(xmlBlob, xmldoc.NewStrictDecoder)
+}
+
+func parseAllRacingCardFiles(xmlBlob []byte, newDecoder func(io.Reader) *xml.Decoder) ([]*RacingCardFile, error) {
+	var objs []*RacingCardFile
+	err := xmldoc.DecodeAll(xmlBlob, newDecoder, func(d *xml.Decoder) error {
+		var obj RacingCardFile
+		if err := d.Decode(&obj); err != nil {
+			return err
+		}
+		objs = append(objs, &obj)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+
+// ParseRacingCardReader unmarshals a single RacingCard XML document read from r
+// to RacingCardFile object. Unlike ParseRacingCardFile the document is
+// streamed without buffering whole file contents in memory. Gzip compressed
+// documents are decompressed transparently.
+func ParseRacingCardReader(r io.Reader) (*RacingCardFile, error) {
+	r, err := xmldoc.Decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseRacingCardReader(r, xmldoc.NewDecoder)
+}
+
+// ParseRacingCardReaderStrictUTF8 is like ParseRacingCardReader, except that
+// documents not encoded in UTF-8 are rejected.
+func ParseRacingCardReaderStrictUTF8(r io.Reader) (*RacingCardFile, error) {
+	r, err := xmldoc.Decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseRacingCardReader(r, xmldoc.NewStrictDecoder)
+}
+
+func parseRacingCardReader(r io.Reader, newDecoder func(io.Reader) *xml.Decoder) (*RacingCardFile, error) {
 	var obj RacingCardFile
-	if err := xml.Unmarshal(xmlBlob, &obj); err != nil {
+	if err := newDecoder(r).Decode(&obj); err != nil {
 		return nil, err
 	}
 	return &obj, nil
 }
+
+// StreamRacingCard decodes RacingCard XML document read from r one meeting at
+// a time and calls fn for every meeting in document order, so whole card is
+// never held in memory. Streaming stops and the error is returned if fn
+// returns an error. Gzip compressed documents are decompressed transparently.
+func StreamRacingCard(r io.Reader, fn func(CardMeeting) error) error {
+	return streamRacingCard(r, fn, xmldoc.NewDecoder)
+}
+
+// StreamRacingCardStrictUTF8 is like StreamRacingCard, except that documents
+// not encoded in UTF-8 are rejected.
+func StreamRacingCardStrictUTF8(r io.Reader, fn func(CardMeeting) error) error {
+	return streamRacingCard(r, fn, xmldoc.NewStrictDecoder)
+}
+
+func streamRacingCard(r io.Reader, fn func(CardMeeting) error, newDecoder func(io.Reader) *xml.Decoder) error {
+	r, err := xmldoc.Decompress(r)
+	if err != nil {
+		return err
+	}
+	d := newDecoder(r)
+	depth := 0
+	for {
+		tok, err := d.Token()
+		if err == io.EOF && depth == 0 {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 1 && t.Name.Local == "Meeting" {
+				var m xmlCardMeeting
+				if err := d.DecodeElement(&m, &t); err != nil {
+					return err
+				}
+				if err := fn(CardMeeting(m)); err != nil {
+					return err
+				}
+				continue
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package horses
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/advbet/pafeed/internal/charset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRacingReader(t *testing.T) {
+	expected := parseTestFile(t, "testdata/feed/b20181128wth12150045.xml")
+	for _, file := range []string{
+		"testdata/feed/b20181128wth12150045.xml",
+		"testdata/gzip/b20181128wth12150045.xml.gz",
+	} {
+		f, err := os.Open(file)
+		require.NoError(t, err)
+		obj, err := ParseRacingReader(f)
+		f.Close()
+		require.NoError(t, err, file)
+		assert.Equal(t, expected, obj, file)
+	}
+}
+
+func TestParseRacingCardReader(t *testing.T) {
+	expected := parseTestCardFile(t, "testdata/feed/c20190227rsh.xml")
+	for _, file := range []string{
+		"testdata/feed/c20190227rsh.xml",
+		"testdata/gzip/c20190227rsh.xml.gz",
+	} {
+		f, err := os.Open(file)
+		require.NoError(t, err)
+		obj, err := ParseRacingCardReader(f)
+		f.Close()
+		require.NoError(t, err, file)
+		assert.Equal(t, expected, obj, file)
+	}
+}
+
+func TestParseAllRacingFiles(t *testing.T) {
+	files := []string{
+		"testdata/feed/b20181128wth12150045.xml",
+		"testdata/feed/b20181201twm08200023.xml",
+	}
+	var blob []byte
+	var expected []*RacingFile
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		blob = append(blob, "\n  \n"...)
+		blob = append(blob, b...)
+		expected = append(expected, parseTestFile(t, file))
+	}
+
+	objs, err := ParseAllRacingFiles(blob)
+	require.NoError(t, err)
+	assert.Equal(t, expected, objs)
+
+	objs, err = ParseAllRacingFiles(blob[:len(blob)-20])
+	assert.Error(t, err)
+	assert.Nil(t, objs)
+
+	objs, err = ParseAllRacingFiles([]byte(" \n"))
+	assert.NoError(t, err)
+	assert.Empty(t, objs)
+}
+
+func TestParseAllRacingFilesCharset(t *testing.T) {
+	files := []string{
+		"testdata/feed/b20181201twm08200023.xml",
+		"testdata/Synthetic/windows-1252-racing.xml",
+		"testdata/feed/b20181128wth12150045.xml",
+		"testdata/Synthetic/windows-1252-racing.xml",
+	}
+	var blob []byte
+	var expected []*RacingFile
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		blob = append(blob, b...)
+		expected = append(expected, parseTestFile(t, file))
+	}
+
+	objs, err := ParseAllRacingFiles(blob)
+	require.NoError(t, err)
+	require.Len(t, objs, 4)
+	assert.Equal(t, "José O’Hughes", objs[1].Meetings[0].Races[0].Horses[0].Jockey.Name)
+	assert.Equal(t, "José O’Hughes", objs[3].Meetings[0].Races[0].Horses[0].Jockey.Name)
+	assert.Equal(t, expected, objs)
+}
+
+func TestParseAllRacingCardFiles(t *testing.T) {
+	file := "testdata/feed/c20190227rsh.xml"
+	b, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	blob := append(append(append([]byte{}, b...), '\n'), b...)
+
+	objs, err := ParseAllRacingCardFiles(blob)
+	require.NoError(t, err)
+	expected := parseTestCardFile(t, file)
+	assert.Equal(t, []*RacingCardFile{expected, expected}, objs)
+}
+
+func TestStreamRacingCard(t *testing.T) {
+	for _, file := range []string{
+		"testdata/feed/c20190227rsh.xml",
+		"testdata/gzip/c20190227rsh.xml.gz",
+	} {
+		f, err := os.Open(file)
+		require.NoError(t, err)
+		var meetings RacingCardFile
+		err = StreamRacingCard(f, func(m CardMeeting) error {
+			meetings = append(meetings, m)
+			return nil
+		})
+		f.Close()
+		require.NoError(t, err, file)
+		assert.Equal(t, parseTestCardFile(t, "testdata/feed/c20190227rsh.xml"), &meetings, file)
+	}
+}
+
+func TestStreamRacingCardMeetings(t *testing.T) {
+	const card = `<?xml version="1.0" encoding="UTF-8"?>
+<HorseRacingCard>
+  <Meeting id="1" course="Lingfield" date="20180414"><Race id="11" date="20180414" time="1355+0100"/></Meeting>
+  <Meeting id="2" course="Aintree" date="20180414"/>
+  <Meeting id="3" course="Wetherby" date="20180414"/>
+</HorseRacingCard>`
+
+	var ids []int
+	err := StreamRacingCard(strings.NewReader(card), func(m CardMeeting) error {
+		ids = append(ids, m.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, ids)
+
+	stop := errors.New("stop")
+	ids = nil
+	err = StreamRacingCard(strings.NewReader(card), func(m CardMeeting) error {
+		ids = append(ids, m.ID)
+		if m.ID == 2 {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, []int{1, 2}, ids)
+
+	err = StreamRacingCard(strings.NewReader(card[:len(card)-30]), func(CardMeeting) error { return nil })
+	assert.Error(t, err)
+}
+
+func TestParseRacingFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		info RacingFileInfo
+		err  bool
+	}{
+		{
+			name: "b20181128wth12150045.xml",
+			info: RacingFileInfo{
+				Date:     time.Date(2018, 11, 28, 0, 0, 0, 0, time.UTC),
+				Course:   "wth",
+				RaceTime: "1215",
+				Sequence: 45,
+			},
+		},
+		{
+			name: "b20180414ain0002.xml",
+			info: RacingFileInfo{
+				Date:     time.Date(2018, 4, 14, 0, 0, 0, 0, time.UTC),
+				Course:   "ain",
+				Sequence: 2,
+			},
+		},
+		{name: "c20190227rsh.xml", err: true},
+		{name: "b20181128wth1215004.xml", err: true},
+		{name: "b20181328wth12150045.xml", err: true},
+		{name: "b20181128w1h12150045.xml", err: true},
+		{name: "b20181128wth25150045.xml", err: true},
+		{name: "b20181128wth1215004x.xml", err: true},
+		{name: "b20181128wth12150045.txt", err: true},
+	}
+
+	for _, test := range tests {
+		info, err := ParseRacingFilename(test.name)
+		if test.err {
+			assert.Error(t, err, test.name)
+			assert.IsType(t, &FilenameError{}, err, test.name)
+			continue
+		}
+		require.NoError(t, err, test.name)
+		assert.Equal(t, test.info, info, test.name)
+	}
+}
+
+func TestParseRacingFileCharset(t *testing.T) {
+	file := "testdata/Synthetic/windows-1252-racing.xml"
+	obj := parseTestFile(t, file)
+	require.Len(t, obj.Meetings, 1)
+	require.Len(t, obj.Meetings[0].Races, 1)
+	require.NotEmpty(t, obj.Meetings[0].Races[0].Horses)
+	assert.Equal(t, "José O’Hughes", obj.Meetings[0].Races[0].Horses[0].Jockey.Name)
+
+	blob, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	_, err = ParseRacingFileStrictUTF8(blob)
+	assert.Error(t, err)
+
+	blob, err = ioutil.ReadFile("testdata/feed/b20181128wth12150045.xml")
+	require.NoError(t, err)
+	strict, err := ParseRacingFileStrictUTF8(blob)
+	require.NoError(t, err)
+	assert.Equal(t, parseTestFile(t, "testdata/feed/b20181128wth12150045.xml"), strict)
+}
+
+func TestParseStrictUTF8(t *testing.T) {
+	racing, err := ioutil.ReadFile("testdata/Synthetic/windows-1252-racing.xml")
+	require.NoError(t, err)
+	card := []byte("<?xml version=\"1.0\" encoding=\"windows-1252\"?>\n<HorseRacingCard><Meeting id=\"1\" course=\"Se\xf1ora\" date=\"20190227\"/></HorseRacingCard>")
+
+	tests := []struct {
+		name   string
+		parse  func([]byte) error
+		strict func([]byte) error
+		blob   []byte
+	}{
+		{
+			name:   "ParseRacingFile",
+			parse:  func(b []byte) error { _, err := ParseRacingFile(b); return err },
+			strict: func(b []byte) error { _, err := ParseRacingFileStrictUTF8(b); return err },
+			blob:   racing,
+		},
+		{
+			name:   "ParseAllRacingFiles",
+			parse:  func(b []byte) error { _, err := ParseAllRacingFiles(b); return err },
+			strict: func(b []byte) error { _, err := ParseAllRacingFilesStrictUTF8(b); return err },
+			blob:   racing,
+		},
+		{
+			name:   "ParseRacingReader",
+			parse:  func(b []byte) error { _, err := ParseRacingReader(bytes.NewReader(b)); return err },
+			strict: func(b []byte) error { _, err := ParseRacingReaderStrictUTF8(bytes.NewReader(b)); return err },
+			blob:   racing,
+		},
+		{
+			name:   "ParseRacingCardFile",
+			parse:  func(b []byte) error { _, err := ParseRacingCardFile(b); return err },
+			strict: func(b []byte) error { _, err := ParseRacingCardFileStrictUTF8(b); return err },
+			blob:   card,
+		},
+		{
+			name:   "ParseAllRacingCardFiles",
+			parse:  func(b []byte) error { _, err := ParseAllRacingCardFiles(b); return err },
+			strict: func(b []byte) error { _, err := ParseAllRacingCardFilesStrictUTF8(b); return err },
+			blob:   card,
+		},
+		{
+			name:   "ParseRacingCardReader",
+			parse:  func(b []byte) error { _, err := ParseRacingCardReader(bytes.NewReader(b)); return err },
+			strict: func(b []byte) error { _, err := ParseRacingCardReaderStrictUTF8(bytes.NewReader(b)); return err },
+			blob:   card,
+		},
+		{
+			name: "StreamRacingCard",
+			parse: func(b []byte) error {
+				return StreamRacingCard(bytes.NewReader(b), func(CardMeeting) error { return nil })
+			},
+			strict: func(b []byte) error {
+				return StreamRacingCardStrictUTF8(bytes.NewReader(b), func(CardMeeting) error { return nil })
+			},
+			blob: card,
+		},
+	}
+
+	for _, test := range tests {
+		assert.NoError(t, test.parse(test.blob), test.name)
+		assert.Error(t, test.strict(test.blob), test.name)
+		r, err := charset.NewReader("windows-1252", bytes.NewReader(test.blob))
+		require.NoError(t, err)
+		utf8, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		utf8 = bytes.Replace(utf8, []byte("windows-1252"), []byte("UTF-8"), 1)
+		assert.NoError(t, test.strict(utf8), test.name)
+	}
+}
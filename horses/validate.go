@@ -0,0 +1,159 @@
+package horses
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError lists all the problems found while validating a document.
+type ValidationError []error
+
+// Error implements error interface.
+func (e ValidationError) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d validation error(s): %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Validate checks document for consistency: race, stewards and horse statuses
+// must be valid, stewards inquiry and objection details must be present when
+// stewards status requires them, finishing positions must be unique unless
+// horses dead-heated, dividend types must be known, dividends must be paid in
+// a single currency and horses referenced by returns must be race runners.
+// ValidationError listing all the problems is returned if document is
+// inconsistent.
+func (f *RacingFile) Validate() error {
+	var errs ValidationError
+	for _, m := range f.Meetings {
+		for _, r := range m.Races {
+			for _, err := range r.validate() {
+				errs = append(errs, fmt.Errorf("meeting %d: race %d: %s", m.ID, r.ID, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Validate checks card document for unknown enum values: meeting status, race
+// type, track type and horse status must be valid if present. Such values are
+// kept as is while parsing. ValidationError listing all the problems is
+// returned if document is inconsistent.
+func (f *RacingCardFile) Validate() error {
+	var errs ValidationError
+	for _, m := range *f {
+		if m.Status != "" && !m.Status.isValid() {
+			errs = append(errs, fmt.Errorf("meeting %d: invalid status: %q", m.ID, m.Status))
+		}
+		for _, r := range m.Races {
+			for _, err := range r.validate() {
+				errs = append(errs, fmt.Errorf("meeting %d: race %d: %s", m.ID, r.ID, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validate returns a list of problems found in a card race.
+func (r *CardRace) validate() []error {
+	var errs []error
+	if r.RaceType != "" && !r.RaceType.isValid() {
+		errs = append(errs, fmt.Errorf("invalid race type: %q", r.RaceType))
+	}
+	if r.TrackType != "" && !r.TrackType.isValid() {
+		errs = append(errs, fmt.Errorf("invalid track type: %q", r.TrackType))
+	}
+	for _, h := range r.Horses {
+		if h.Status != "" && !h.Status.isValid() {
+			errs = append(errs, fmt.Errorf("horse %d: invalid status: %q", h.ID, h.Status))
+		}
+	}
+	return errs
+}
+
+// validate returns a list of problems found in a race.
+func (r *Race) validate() []error {
+	var errs []error
+	if !r.Status.isValid() {
+		errs = append(errs, fmt.Errorf("invalid status: %q", r.Status))
+	}
+	if !r.Stewards.isValid() {
+		errs = append(errs, fmt.Errorf("invalid stewards status: %q", r.Stewards))
+	}
+	inquiry := r.Stewards == StewardsInquiry || r.Stewards == StewardsInquiryAndObjection
+	if inquiry && r.StewardsInquiry == "" {
+		errs = append(errs, fmt.Errorf("stewards status is %s but inquiry details are missing", r.Stewards))
+	}
+	objection := r.Stewards == StewardsObjection || r.Stewards == StewardsInquiryAndObjection
+	if objection && r.StewardsObjection == "" {
+		errs = append(errs, fmt.Errorf("stewards status is %s but objection details are missing", r.Stewards))
+	}
+
+	runners := make(map[int]bool, len(r.Horses))
+	positions := make(map[int][]Horse)
+	var order []int // Finish positions in document order of horses
+	for _, h := range r.Horses {
+		if !h.Status.isValid() {
+			errs = append(errs, fmt.Errorf("horse %d: invalid status: %q", h.ID, h.Status))
+		}
+		runners[h.ID] = true
+		if h.Result != nil && h.Result.FinishPos != 0 {
+			pos := h.Result.FinishPos
+			if _, ok := positions[pos]; !ok {
+				order = append(order, pos)
+			}
+			positions[pos] = append(positions[pos], h)
+		}
+	}
+	for _, pos := range order {
+		if horses := positions[pos]; len(horses) > 1 && !isDeadHeat(horses) {
+			errs = append(errs, fmt.Errorf("finish position %d is shared by %d horses", pos, len(horses)))
+		}
+	}
+
+	if r.Returns == nil {
+		return errs
+	}
+	if currencies := r.Returns.Currencies(); len(currencies) > 1 {
+		errs = append(errs, fmt.Errorf("dividends are paid in several currencies: %s", strings.Join(currencies, ", ")))
+	}
+	for _, t := range r.Returns.Tote {
+		if !t.Type.isValid() {
+			errs = append(errs, fmt.Errorf("unknown tote dividend type: %q", t.Type))
+		}
+		for _, ref := range t.HorseRef {
+			if !runners[ref.ID] {
+				errs = append(errs, fmt.Errorf("%s tote dividend references unknown horse %d", t.Type, ref.ID))
+			}
+		}
+	}
+	for _, b := range r.Returns.Bet {
+		if !b.Type.isValid() {
+			errs = append(errs, fmt.Errorf("unknown dividend type: %q", b.Type))
+		}
+		for _, ref := range b.HorseRef {
+			if !runners[ref.ID] {
+				errs = append(errs, fmt.Errorf("%s dividend references unknown horse %d", b.Type, ref.ID))
+			}
+		}
+	}
+	return errs
+}
+
+// isDeadHeat returns true if horses sharing the same finishing position
+// dead-heated.
+func isDeadHeat(horses []Horse) bool {
+	for _, h := range horses {
+		if strings.EqualFold(h.Result.BetweenDistance, "Dead Heat") {
+			return true
+		}
+	}
+	return false
+}
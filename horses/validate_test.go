@@ -0,0 +1,138 @@
+package horses
+
+import (
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFeed(t *testing.T) {
+	dirs := []string{
+		"testdata/Abandoned",
+		"testdata/Aintree",
+		"testdata/EdgeCases",
+		"testdata/GreyvilleJockeyChanges",
+		"testdata/Lingfield",
+		"testdata/NewcastleRule4AllBets",
+		"testdata/VaalLateWithdrawal",
+		"testdata/WindsorRule4BoardPrices",
+		"testdata/feed",
+	}
+
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		require.NoError(t, err, dir)
+		for _, f := range files {
+			if !strings.HasPrefix(f.Name(), "b") {
+				continue
+			}
+			path := path.Join(dir, f.Name())
+			assert.NoError(t, parseTestFile(t, path).Validate(), path)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	obj := RacingFile{
+		Meetings: []Meeting{
+			{
+				ID: 1,
+				Races: []Race{
+					{
+						ID:       1,
+						Status:   "Unknown",
+						Stewards: StewardsInquiryAndObjection,
+						Horses: []Horse{
+							{ID: 1, Status: HorseRunner, Result: &Result{FinishPos: 1}},
+							{ID: 2, Status: HorseRunner, Result: &Result{FinishPos: 1}},
+							{ID: 3, Status: HorseRunner, Result: &Result{FinishPos: 3}},
+							{ID: 4, Status: HorseRunner, Result: &Result{FinishPos: 3, BetweenDistance: "Dead Heat"}},
+						},
+						Returns: &Returns{
+							Tote: []Tote{
+								{Type: ToteWin, HorseRef: []HorseRef{{ID: 1}}},
+								{Type: "Quadpot", HorseRef: []HorseRef{{ID: 1}}},
+							},
+							Bet: []Bet{{Type: BetTypeCSF, HorseRef: []HorseRef{{ID: 1}, {ID: 5}}}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := obj.Validate()
+	require.Error(t, err)
+	errs, ok := err.(ValidationError)
+	require.True(t, ok)
+	assert.Len(t, errs, 6)
+}
+
+func TestValidateFinishPositionsOrder(t *testing.T) {
+	obj := RacingFile{
+		Meetings: []Meeting{
+			{
+				ID: 1,
+				Races: []Race{
+					{
+						ID:       1,
+						Status:   RaceResult,
+						Stewards: StewardsNone,
+						Horses: []Horse{
+							{ID: 1, Status: HorseRunner, Result: &Result{FinishPos: 3}},
+							{ID: 2, Status: HorseRunner, Result: &Result{FinishPos: 1}},
+							{ID: 3, Status: HorseRunner, Result: &Result{FinishPos: 3}},
+							{ID: 4, Status: HorseRunner, Result: &Result{FinishPos: 1}},
+							{ID: 5, Status: HorseRunner, Result: &Result{FinishPos: 2}},
+							{ID: 6, Status: HorseRunner, Result: &Result{FinishPos: 2}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expected := "3 validation error(s): " +
+		"meeting 1: race 1: finish position 3 is shared by 2 horses; " +
+		"meeting 1: race 1: finish position 1 is shared by 2 horses; " +
+		"meeting 1: race 1: finish position 2 is shared by 2 horses"
+	for i := 0; i < 10; i++ {
+		assert.EqualError(t, obj.Validate(), expected)
+	}
+}
+
+func TestValidateReturnsCurrencies(t *testing.T) {
+	obj := RacingFile{
+		Meetings: []Meeting{
+			{
+				ID: 1,
+				Races: []Race{
+					{
+						ID:       1,
+						Status:   RaceResult,
+						Stewards: StewardsNone,
+						Horses:   []Horse{{ID: 1, Status: HorseRunner, Result: &Result{FinishPos: 1}}},
+						Returns: &Returns{
+							Tote: []Tote{{Type: ToteWin, Currency: "EUR", HorseRef: []HorseRef{{ID: 1}}}},
+							Bet:  []Bet{{Type: BetTypeCSF, Currency: "GBP", HorseRef: []HorseRef{{ID: 1}}}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := obj.Validate()
+	require.Error(t, err)
+	errs, ok := err.(ValidationError)
+	require.True(t, ok)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "EUR, GBP")
+
+	obj.Meetings[0].Races[0].Returns.Bet[0].Currency = "EUR"
+	assert.NoError(t, obj.Validate())
+}
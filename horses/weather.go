@@ -0,0 +1,91 @@
+package horses
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// WeatherCondition is an enum of weather condition keywords.
+type WeatherCondition string
+
+// List of recognised WeatherCondition values.
+const (
+	WeatherSunny    WeatherCondition = "Sunny"
+	WeatherFine     WeatherCondition = "Fine"
+	WeatherDry      WeatherCondition = "Dry"
+	WeatherBright   WeatherCondition = "Bright"
+	WeatherCloudy   WeatherCondition = "Cloudy"
+	WeatherOvercast WeatherCondition = "Overcast"
+	WeatherShowers  WeatherCondition = "Showers"
+	WeatherDrizzle  WeatherCondition = "Drizzle"
+	WeatherRain     WeatherCondition = "Rain"
+	WeatherFog      WeatherCondition = "Fog"
+	WeatherWindy    WeatherCondition = "Windy"
+	WeatherSnow     WeatherCondition = "Snow"
+)
+
+// Weather is a structured weather description.
+type Weather struct {
+	Raw         string             // Weather description as received
+	Conditions  []WeatherCondition // Recognised conditions in order of appearance
+	Temperature *int               // Temperature in degrees Celsius, nil if not mentioned
+}
+
+// weatherWords maps lower case words used by PA to weather conditions.
+var weatherWords = map[string]WeatherCondition{
+	"sunny":    WeatherSunny,
+	"sun":      WeatherSunny,
+	"sunshine": WeatherSunny,
+	"fine":     WeatherFine,
+	"dry":      WeatherDry,
+	"bright":   WeatherBright,
+	"cloudy":   WeatherCloudy,
+	"cloud":    WeatherCloudy,
+	"overcast": WeatherOvercast,
+	"showers":  WeatherShowers,
+	"shower":   WeatherShowers,
+	"showery":  WeatherShowers,
+	"drizzle":  WeatherDrizzle,
+	"rain":     WeatherRain,
+	"raining":  WeatherRain,
+	"wet":      WeatherRain,
+	"fog":      WeatherFog,
+	"foggy":    WeatherFog,
+	"mist":     WeatherFog,
+	"misty":    WeatherFog,
+	"windy":    WeatherWindy,
+	"wind":     WeatherWindy,
+	"breezy":   WeatherWindy,
+	"snow":     WeatherSnow,
+	"snowing":  WeatherSnow,
+}
+
+// temperatureRe matches temperature written in degrees Celsius, e.g. "12C",
+// "12°C" or "12 degrees".
+var temperatureRe = regexp.MustCompile(`(?i)(-?\d+)\s*(?:°\s*c|c|degrees?)\b`)
+
+// ParseWeather parses weather description, e.g. "Overcast & Showers" is parsed
+// to Overcast and Showers conditions. Unrecognised words are ignored, the
+// description is kept in Raw.
+func ParseWeather(s string) Weather {
+	w := Weather{Raw: s}
+	if m := temperatureRe.FindStringSubmatch(s); m != nil {
+		if t, err := strconv.Atoi(m[1]); err == nil {
+			w.Temperature = &t
+		}
+	}
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return r < 'a' || r > 'z'
+	})
+	seen := make(map[WeatherCondition]bool)
+	for _, word := range words {
+		c, ok := weatherWords[word]
+		if !ok || seen[c] {
+			continue
+		}
+		seen[c] = true
+		w.Conditions = append(w.Conditions, c)
+	}
+	return w
+}
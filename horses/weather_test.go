@@ -0,0 +1,60 @@
+package horses
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWeather(t *testing.T) {
+	temp := func(t int) *int { return &t }
+	tests := []struct {
+		s       string
+		weather Weather
+	}{
+		{
+			s:       "Overcast & Showers",
+			weather: Weather{Raw: "Overcast & Showers", Conditions: []WeatherCondition{WeatherOvercast, WeatherShowers}},
+		},
+		{
+			s:       "Fine & Dry",
+			weather: Weather{Raw: "Fine & Dry", Conditions: []WeatherCondition{WeatherFine, WeatherDry}},
+		},
+		{
+			s:       "Sunny",
+			weather: Weather{Raw: "Sunny", Conditions: []WeatherCondition{WeatherSunny}},
+		},
+		{
+			s:       "Raining",
+			weather: Weather{Raw: "Raining", Conditions: []WeatherCondition{WeatherRain}},
+		},
+		{
+			s:       "Cloudy, light rain, 8C",
+			weather: Weather{Raw: "Cloudy, light rain, 8C", Conditions: []WeatherCondition{WeatherCloudy, WeatherRain}, Temperature: temp(8)},
+		},
+		{
+			s:       "Sunny spells and sunshine 21 degrees",
+			weather: Weather{Raw: "Sunny spells and sunshine 21 degrees", Conditions: []WeatherCondition{WeatherSunny}, Temperature: temp(21)},
+		},
+		{
+			s:       "Frosty -2°C",
+			weather: Weather{Raw: "Frosty -2°C", Temperature: temp(-2)},
+		},
+		{
+			s:       " ",
+			weather: Weather{Raw: " "},
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.weather, ParseWeather(test.s), test.s)
+	}
+}
+
+func TestParseWeatherFeed(t *testing.T) {
+	race := testRace(t, "testdata/feed/b20181128wth12150045.xml")
+	w := ParseWeather(race.Weather)
+	assert.Equal(t, race.Weather, w.Raw)
+	assert.Equal(t, []WeatherCondition{WeatherOvercast, WeatherShowers}, w.Conditions)
+	assert.Nil(t, w.Temperature)
+}
@@ -0,0 +1,82 @@
+// Package charset implements decoding of single byte character sets found in
+// historical PA feed documents.
+package charset
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// windows1252 maps bytes 0x80-0x9F of windows-1252 charset to unicode code
+// points. Unused positions are mapped to the unicode replacement character.
+var windows1252 = [32]rune{
+	'€', utf8.RuneError, '‚', 'ƒ', '„', '…', '†', '‡',
+	'ˆ', '‰', 'Š', '‹', 'Œ', utf8.RuneError, 'Ž', utf8.RuneError,
+	utf8.RuneError, '‘', '’', '“', '”', '•', '–', '—',
+	'˜', '™', 'š', '›', 'œ', utf8.RuneError, 'ž', 'Ÿ',
+}
+
+// NewReader returns a reader converting input in the given charset to UTF-8.
+// Windows-1252 and ISO-8859-1 (latin1) charsets are supported. It is suitable
+// to be used as xml.Decoder CharsetReader.
+func NewReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "windows-1252", "cp1252":
+		return &reader{r: bufio.NewReader(input), decode: decodeWindows1252}, nil
+	case "iso-8859-1", "iso_8859-1", "latin1", "l1":
+		return &reader{r: bufio.NewReader(input), decode: decodeLatin1}, nil
+	default:
+		return nil, fmt.Errorf("unsupported charset: %s", charset)
+	}
+}
+
+func decodeLatin1(b byte) rune {
+	return rune(b)
+}
+
+func decodeWindows1252(b byte) rune {
+	if b >= 0x80 && b < 0xA0 {
+		return windows1252[b-0x80]
+	}
+	return rune(b)
+}
+
+// reader converts single byte charset input to UTF-8.
+type reader struct {
+	r       *bufio.Reader
+	decode  func(byte) rune
+	buf     [utf8.UTFMax]byte
+	pending []byte // encoded bytes of the last rune not yet returned
+}
+
+// Read implements io.Reader interface.
+func (r *reader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.pending) > 0 {
+			c := copy(p[n:], r.pending)
+			r.pending = r.pending[c:]
+			n += c
+			continue
+		}
+		b, err := r.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		ch := r.decode(b)
+		if ch < utf8.RuneSelf {
+			p[n] = byte(ch)
+			n++
+			continue
+		}
+		l := utf8.EncodeRune(r.buf[:], ch)
+		r.pending = r.buf[:l]
+	}
+	return n, nil
+}
@@ -0,0 +1,45 @@
+package charset
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReader(t *testing.T) {
+	tests := []struct {
+		charset  string
+		input    string
+		expected string
+	}{
+		{
+			charset:  "windows-1252",
+			input:    "Jos\xe9 O\x92Brien \x80",
+			expected: "José O’Brien €",
+		},
+		{
+			charset:  "ISO-8859-1",
+			input:    "Jos\xe9",
+			expected: "José",
+		},
+		{
+			charset:  "cp1252",
+			input:    "plain ascii",
+			expected: "plain ascii",
+		},
+	}
+
+	for _, test := range tests {
+		r, err := NewReader(test.charset, strings.NewReader(test.input))
+		require.NoError(t, err, test.charset)
+		out, err := ioutil.ReadAll(r)
+		require.NoError(t, err, test.charset)
+		assert.Equal(t, test.expected, string(out), test.charset)
+	}
+
+	_, err := NewReader("koi8-r", strings.NewReader(""))
+	assert.Error(t, err)
+}
@@ -0,0 +1,42 @@
+package xmldoc
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"io"
+
+	"github.com/advbet/pafeed/internal/charset"
+)
+
+// gzipMagic is the header of gzip compressed data.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Decompress returns a reader of decompressed data if r holds gzip compressed
+// data, otherwise data is read as is.
+func Decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if !bytes.Equal(header, gzipMagic) {
+		return br, nil
+	}
+	return gzip.NewReader(br)
+}
+
+// NewDecoder returns XML decoder reading from r. Windows-1252 and latin1
+// encoded documents found in historical feed archives are decoded to UTF-8.
+func NewDecoder(r io.Reader) *xml.Decoder {
+	d := xml.NewDecoder(r)
+	d.CharsetReader = charset.NewReader
+	return d
+}
+
+// NewStrictDecoder returns XML decoder reading from r that accepts UTF-8
+// encoded documents only, documents declaring any other charset are rejected.
+func NewStrictDecoder(r io.Reader) *xml.Decoder {
+	return xml.NewDecoder(r)
+}
@@ -0,0 +1,44 @@
+package xmldoc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecompress(t *testing.T) {
+	const doc = `<?xml version="1.0"?><a/>`
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	_, err := w.Write([]byte(doc))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	for _, input := range [][]byte{[]byte(doc), compressed.Bytes(), {}} {
+		r, err := Decompress(bytes.NewReader(input))
+		require.NoError(t, err)
+		output, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		if len(input) == 0 {
+			assert.Empty(t, output)
+			continue
+		}
+		assert.Equal(t, doc, string(output))
+	}
+}
+
+func TestNewDecoder(t *testing.T) {
+	doc := "<?xml version=\"1.0\" encoding=\"windows-1252\"?><a>Se\xf1ora</a>"
+
+	var v string
+	require.NoError(t, NewDecoder(strings.NewReader(doc)).Decode(&v))
+	assert.Equal(t, "Señora", v)
+
+	assert.Error(t, NewStrictDecoder(strings.NewReader(doc)).Decode(&v))
+	assert.NoError(t, NewStrictDecoder(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?><a>Señora</a>`)).Decode(&v))
+}
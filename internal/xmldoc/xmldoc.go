@@ -0,0 +1,76 @@
+// Package xmldoc implements reading of PA feed XML documents shared by the
+// feed packages: charset aware decoding, gzip decompression and reading of
+// several XML documents concatenated into a single file.
+package xmldoc
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// declaration is the start of XML declaration opening a document.
+var declaration = []byte("<?xml")
+
+// DecodeAll calls decode for every XML document of xmlBlob holding several
+// documents back to back. Contents are split at XML declarations and every part
+// is read by a new decoder created by newDecoder, so that the charset declared
+// by one document is not applied to the documents following it. Whitespace
+// between documents is skipped. Decode should unmarshal a single document, it
+// is called until it returns io.EOF. Errors are prefixed with the document
+// number.
+func DecodeAll(xmlBlob []byte, newDecoder func(io.Reader) *xml.Decoder, decode func(*xml.Decoder) error) error {
+	var n int
+	for _, part := range split(xmlBlob) {
+		d := newDecoder(bytes.NewReader(part))
+		for {
+			err := decode(d)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("document %d: %w", n+1, err)
+			}
+			n++
+		}
+	}
+	return nil
+}
+
+// split splits xmlBlob before every XML declaration. Declarations are not
+// expected to appear in comments or CDATA sections.
+func split(xmlBlob []byte) [][]byte {
+	var parts [][]byte
+	var start int
+	for offset := 1; offset < len(xmlBlob); {
+		i := bytes.Index(xmlBlob[offset:], declaration)
+		if i < 0 {
+			break
+		}
+		i += offset
+		if isDeclaration(xmlBlob[i:]) {
+			parts = append(parts, xmlBlob[start:i])
+			start = i
+		}
+		offset = i + 1
+	}
+	if start < len(xmlBlob) {
+		parts = append(parts, xmlBlob[start:])
+	}
+	return parts
+}
+
+// isDeclaration returns true if b starts with XML declaration, as opposed to
+// a processing instruction having target such as xml-stylesheet.
+func isDeclaration(b []byte) bool {
+	if len(b) <= len(declaration) {
+		return false
+	}
+	switch b[len(declaration)] {
+	case ' ', '\t', '\r', '\n':
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,62 @@
+package xmldoc
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{input: "", expected: nil},
+		{input: "<a/>", expected: []string{"<a/>"}},
+		{input: "<a/><b/>", expected: []string{"<a/><b/>"}},
+		{
+			input:    "<?xml version=\"1.0\"?><a/>\n<?xml version=\"1.0\"?><b/>",
+			expected: []string{"<?xml version=\"1.0\"?><a/>\n", "<?xml version=\"1.0\"?><b/>"},
+		},
+		{
+			input:    "<a/><?xml\tversion=\"1.0\"?><b/>",
+			expected: []string{"<a/>", "<?xml\tversion=\"1.0\"?><b/>"},
+		},
+		{
+			input:    "<?xml-stylesheet href=\"a.xsl\"?><a/>",
+			expected: []string{"<?xml-stylesheet href=\"a.xsl\"?><a/>"},
+		},
+	}
+
+	for _, test := range tests {
+		var parts []string
+		for _, p := range split([]byte(test.input)) {
+			parts = append(parts, string(p))
+		}
+		assert.Equal(t, test.expected, parts, test.input)
+	}
+}
+
+func TestDecodeAll(t *testing.T) {
+	blob := []byte("<?xml version=\"1.0\"?><a>1</a> <a>2</a>\n<?xml version=\"1.0\"?><a>3</a><a>4")
+	var values []string
+	decode := func(d *xml.Decoder) error {
+		var v string
+		if err := d.Decode(&v); err != nil {
+			return err
+		}
+		values = append(values, v)
+		return nil
+	}
+
+	err := DecodeAll(blob, xml.NewDecoder, decode)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "document 4")
+	assert.Equal(t, []string{"1", "2", "3"}, values)
+
+	values = nil
+	require.NoError(t, DecodeAll(blob[:len(blob)-4], xml.NewDecoder, decode))
+	assert.Equal(t, []string{"1", "2", "3"}, values)
+}
@@ -0,0 +1,114 @@
+package pafeed
+
+import (
+	"sort"
+
+	"github.com/advbet/pafeed/greyhounds"
+	"github.com/advbet/pafeed/horses"
+)
+
+// Runner is a sport independent description of a race finisher.
+type Runner struct {
+	ID       int    // Horse or dog identifier
+	Name     string // Horse or dog name
+	Number   int    // Saddlecloth number for horses, trap number for greyhounds
+	Position int    // Finishing position, dead-heated runners share position
+}
+
+// RaceResult is a sport independent view of a race result.
+type RaceResult interface {
+	// Winner returns the race winner, false is returned if the race has no
+	// result yet.
+	Winner() (Runner, bool)
+	// FinishingOrder returns placed runners ordered by finishing position.
+	// Disqualified runners and runners that failed to finish are omitted.
+	FinishingOrder() []Runner
+}
+
+// HorseRaceResult returns a RaceResult view of a horse race. Amended
+// position takes precedence over the first past the post position.
+func HorseRaceResult(r *horses.Race) RaceResult {
+	return horseRace{r}
+}
+
+// GreyhoundRaceResult returns a RaceResult view of a greyhound race.
+func GreyhoundRaceResult(r *greyhounds.Race) RaceResult {
+	return greyhoundRace{r}
+}
+
+type horseRace struct {
+	race *horses.Race
+}
+
+// FinishingOrder implements RaceResult interface.
+func (r horseRace) FinishingOrder() []Runner {
+	var runners []Runner
+	for _, h := range r.race.Horses {
+		if h.Result == nil {
+			continue
+		}
+		pos := h.Result.AmendedPos
+		if pos == 0 && !h.Result.Disqualified {
+			pos = h.Result.FinishPos
+		}
+		if pos == 0 {
+			continue
+		}
+		runners = append(runners, Runner{
+			ID:       h.ID,
+			Name:     h.Name,
+			Number:   h.ClothNumber,
+			Position: pos,
+		})
+	}
+	sortRunners(runners)
+	return runners
+}
+
+// Winner implements RaceResult interface.
+func (r horseRace) Winner() (Runner, bool) {
+	return winner(r.FinishingOrder())
+}
+
+type greyhoundRace struct {
+	race *greyhounds.Race
+}
+
+// FinishingOrder implements RaceResult interface.
+func (r greyhoundRace) FinishingOrder() []Runner {
+	var runners []Runner
+	for _, t := range r.race.FinishingOrder() {
+		if t.Dog == nil {
+			continue
+		}
+		pos, _ := greyhounds.ParseResult(t.Result.Position)
+		runners = append(runners, Runner{
+			ID:       t.Dog.ID,
+			Name:     t.Dog.Name,
+			Number:   t.TrapNo,
+			Position: pos,
+		})
+	}
+	return runners
+}
+
+// Winner implements RaceResult interface.
+func (r greyhoundRace) Winner() (Runner, bool) {
+	return winner(r.FinishingOrder())
+}
+
+// sortRunners orders runners by finishing position, original order of
+// dead-heated runners is kept.
+func sortRunners(runners []Runner) {
+	sort.SliceStable(runners, func(i, j int) bool {
+		return runners[i].Position < runners[j].Position
+	})
+}
+
+// winner returns the first runner of the finishing order if it has won.
+func winner(order []Runner) (Runner, bool) {
+	if len(order) == 0 || order[0].Position != 1 {
+		return Runner{}, false
+	}
+	return order[0], true
+}
@@ -0,0 +1,83 @@
+package pafeed
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/advbet/pafeed/greyhounds"
+	"github.com/advbet/pafeed/horses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHorseRaceResult(t *testing.T) {
+	blob, err := ioutil.ReadFile("horses/testdata/feed/b20181128wth12150045.xml")
+	require.NoError(t, err)
+	obj, err := horses.ParseRacingFile(blob)
+	require.NoError(t, err)
+	require.Len(t, obj.Meetings, 1)
+	require.Len(t, obj.Meetings[0].Races, 1)
+	res := HorseRaceResult(&obj.Meetings[0].Races[0])
+
+	w, ok := res.Winner()
+	require.True(t, ok)
+	assert.Equal(t, Runner{ID: 2338916, Name: "Fabianski", Number: 7, Position: 1}, w)
+
+	order := res.FinishingOrder()
+	require.Len(t, order, 6)
+	for i, r := range order {
+		assert.Equal(t, i+1, r.Position)
+	}
+	assert.Equal(t, "Alliteration", order[1].Name)
+	assert.Equal(t, "Pepper Street", order[2].Name)
+
+	// disqualified winner, amended result
+	race := horses.Race{
+		Horses: []horses.Horse{
+			{ID: 1, Result: &horses.Result{FinishPos: 1, Disqualified: true}},
+			{ID: 2, Result: &horses.Result{FinishPos: 2, AmendedPos: 1}},
+			{ID: 3},
+		},
+	}
+	res = HorseRaceResult(&race)
+	w, ok = res.Winner()
+	require.True(t, ok)
+	assert.Equal(t, 2, w.ID)
+	assert.Len(t, res.FinishingOrder(), 1)
+
+	_, ok = HorseRaceResult(&horses.Race{}).Winner()
+	assert.False(t, ok)
+}
+
+func TestGreyhoundRaceResult(t *testing.T) {
+	blob, err := ioutil.ReadFile("greyhounds/testdata/Crayford/b2018041433736119270028.xml")
+	require.NoError(t, err)
+	obj, err := greyhounds.ParseFile(blob)
+	require.NoError(t, err)
+	require.Len(t, obj.Meetings, 1)
+	require.Len(t, obj.Meetings[0].Races, 1)
+	res := GreyhoundRaceResult(&obj.Meetings[0].Races[0])
+
+	w, ok := res.Winner()
+	require.True(t, ok)
+	assert.Equal(t, Runner{ID: 478812, Name: "Clonmannon Lady", Number: 1, Position: 1}, w)
+
+	order := res.FinishingOrder()
+	require.NotEmpty(t, order)
+	for i := 1; i < len(order); i++ {
+		assert.True(t, order[i-1].Position <= order[i].Position)
+	}
+	assert.Equal(t, "Kelva Matty", order[1].Name)
+
+	// disqualified dogs are not placed
+	race := greyhounds.Race{
+		Traps: []greyhounds.Trap{
+			{TrapNo: 1, Dog: &greyhounds.Dog{ID: 1}, Result: &greyhounds.Result{Position: "DSQ"}},
+			{TrapNo: 2, Dog: &greyhounds.Dog{ID: 2}, Result: &greyhounds.Result{Position: "2"}},
+		},
+	}
+	res = GreyhoundRaceResult(&race)
+	_, ok = res.Winner()
+	assert.False(t, ok)
+	assert.Len(t, res.FinishingOrder(), 1)
+}